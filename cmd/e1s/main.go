@@ -55,12 +55,19 @@ func init() {
 	rootCmd.Flags().IntP("refresh", "r", 30, "specify the default refresh rate as an integer (sec), sets -1 to stop auto refresh")
 	rootCmd.Flags().String("profile", "", "specify the AWS profile")
 	rootCmd.Flags().String("region", "", "specify the AWS region")
+	rootCmd.Flags().StringSlice("regions", nil, "list clusters of several AWS regions together, comma separated")
 	rootCmd.Flags().String("theme", "", "specify color theme")
 	rootCmd.Flags().String("cluster", "", "specify the default cluster")
 	rootCmd.Flags().String("service", "", "specify the default service (requires --cluster)")
 	rootCmd.Flags().Bool("splash", true, "display startup splash screen (AWS load runs before the UI)")
 	rootCmd.Flags().String("exec-mode", "ecs", "execution mode for ECS containers: ecs or ssm")
 	rootCmd.Flags().String("ssm-custom-command", "", "custom command template for SSM container execution mode")
+	rootCmd.Flags().Int("api-timeout", 30, "specify the AWS API call timeout as an integer (sec), sets -1 to disable the timeout")
+	rootCmd.Flags().Bool("demo", false, "browse a bundled demo snapshot without AWS credentials")
+	rootCmd.Flags().String("fixtures", "", "browse resources from a JSON or YAML snapshot file instead of AWS")
+	rootCmd.Flags().String("endpoint-url", "", "specify the endpoint of AWS services, e.g. LocalStack")
+	rootCmd.Flags().Bool("service-utilization", false, "show CPU% and Mem% columns in services table")
+	rootCmd.Flags().String("console-url-template", "", "sign-in URL template wrapping console URLs, e.g. an SSO portal")
 
 	err := viper.BindPFlags(rootCmd.Flags())
 	if err != nil {
@@ -89,6 +96,11 @@ Check https://github.com/keidarcy/e1s for more details.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		profile := viper.GetString("profile")
 		region := viper.GetString("region")
+		regions := viper.GetStringSlice("regions")
+		// First of listed regions is home region unless given
+		if region == "" && len(regions) > 0 {
+			region = regions[0]
+		}
 		if profile != "" {
 			os.Setenv("AWS_PROFILE", profile)
 			defer func() {
@@ -114,21 +126,44 @@ Check https://github.com/keidarcy/e1s for more details.`,
 		splash := viper.GetBool("splash")
 		execMode := viper.GetString("exec-mode")
 		ssmCustomCommand := viper.GetString("ssm-custom-command")
+		apiTimeout := viper.GetInt("api-timeout")
+		demo := viper.GetBool("demo")
+		fixtures := viper.GetString("fixtures")
+		endpointURL := viper.GetString("endpoint-url")
+		// Per service endpoints are only read from config file
+		endpoints := viper.GetStringMapString("endpoints")
+		consoleURLTemplate := viper.GetString("console-url-template")
+		serviceUtilization := viper.GetBool("service-utilization")
+		// Log format is only read from config file
+		var logFormat e1s.LogFormat
+		if err := viper.UnmarshalKey("log-format", &logFormat); err != nil {
+			fmt.Println("Error reading log-format config:", err)
+			os.Exit(1)
+		}
 
 		option := e1s.Option{
-			ConfigFile:       configFile,
-			LogFile:          logFile,
-			Debug:            debug,
-			JSON:             json,
-			ReadOnly:         readOnly,
-			Refresh:          refresh,
-			Shell:            shell,
-			Theme:            theme,
-			Cluster:          cluster,
-			Service:          service,
-			Splash:           splash,
-			ExecMode:         execMode,
-			SsmCustomCommand: ssmCustomCommand,
+			ConfigFile:         configFile,
+			LogFile:            logFile,
+			Debug:              debug,
+			JSON:               json,
+			ReadOnly:           readOnly,
+			Refresh:            refresh,
+			Shell:              shell,
+			Theme:              theme,
+			Cluster:            cluster,
+			Service:            service,
+			Splash:             splash,
+			ExecMode:           execMode,
+			SsmCustomCommand:   ssmCustomCommand,
+			Demo:               demo,
+			Fixtures:           fixtures,
+			Timeout:            apiTimeout,
+			Regions:            regions,
+			EndpointURL:        endpointURL,
+			Endpoints:          endpoints,
+			ConsoleURLTemplate: consoleURLTemplate,
+			ServiceUtilization: serviceUtilization,
+			LogFormat:          logFormat,
 		}
 
 		if err := e1s.Start(option); err != nil {
@@ -16,28 +16,31 @@ type AutoScalingData struct {
 	Activities []types.ScalingActivity
 }
 
-func (store *Store) GetAutoscaling(serviceArn *string) (*AutoScalingData, error) {
+func (store *Store) GetAutoscaling(ctx context.Context, serviceArn *string) (*AutoScalingData, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	store.initAutoScalingClient()
 
-	targets, err := store.describeScalableTargets(serviceArn)
+	targets, err := store.describeScalableTargets(ctx, serviceArn)
 
 	if err != nil {
 		return nil, err
 	}
 
-	policies, err := store.describeScalingPolicies(serviceArn)
+	policies, err := store.describeScalingPolicies(ctx, serviceArn)
 
 	if err != nil {
 		return nil, err
 	}
 
-	activities, err := store.describeScalingActivities(serviceArn)
+	activities, err := store.describeScalingActivities(ctx, serviceArn)
 
 	if err != nil {
 		return nil, err
 	}
 
-	actions, err := store.describeScheduledAction(serviceArn)
+	actions, err := store.describeScheduledAction(ctx, serviceArn)
 
 	if err != nil {
 		return nil, err
@@ -55,13 +58,13 @@ func (store *Store) GetAutoscaling(serviceArn *string) (*AutoScalingData, error)
 // Equivalent to
 // aws application-autoscaling describe-scaling-activities --service-namespace ecs --resource-id {ServiceArn}
 // Auto scaling logs
-func (store *Store) describeScalingActivities(serviceArn *string) ([]types.ScalingActivity, error) {
+func (store *Store) describeScalingActivities(ctx context.Context, serviceArn *string) ([]types.ScalingActivity, error) {
 	activitiesInput := &applicationautoscaling.DescribeScalingActivitiesInput{
 		ServiceNamespace: "ecs",
 		ResourceId:       serviceArn,
 		MaxResults:       aws.Int32(10),
 	}
-	activitiesOutput, err := store.autoScaling.DescribeScalingActivities(context.Background(), activitiesInput)
+	activitiesOutput, err := store.autoScaling.DescribeScalingActivities(ctx, activitiesInput)
 
 	if err != nil {
 		slog.Warn("failed to run aws api to auto scaling activities", "serviceArn", *serviceArn, "error", err)
@@ -73,12 +76,12 @@ func (store *Store) describeScalingActivities(serviceArn *string) ([]types.Scali
 
 // Equivalent to
 // aws application-autoscaling describe-scalable-targets --service-namespace ecs --resource-ids {[ServiceArn]}
-func (store *Store) describeScalableTargets(serviceArn *string) ([]types.ScalableTarget, error) {
+func (store *Store) describeScalableTargets(ctx context.Context, serviceArn *string) ([]types.ScalableTarget, error) {
 	targetsInput := &applicationautoscaling.DescribeScalableTargetsInput{
 		ServiceNamespace: "ecs",
 		ResourceIds:      []string{*serviceArn},
 	}
-	targetsOutput, err := store.autoScaling.DescribeScalableTargets(context.Background(), targetsInput)
+	targetsOutput, err := store.autoScaling.DescribeScalableTargets(ctx, targetsInput)
 
 	if err != nil {
 		slog.Warn("failed to run aws api to auto scaling activities", "serviceArn", *serviceArn, "error", err)
@@ -90,12 +93,12 @@ func (store *Store) describeScalableTargets(serviceArn *string) ([]types.Scalabl
 
 // Equivalent to
 // aws application-autoscaling describe-scaling-policies --service-namespace ecs --resource-id "service/<ClusterName>/<ServiceName>"
-func (store *Store) describeScalingPolicies(serviceArn *string) ([]types.ScalingPolicy, error) {
+func (store *Store) describeScalingPolicies(ctx context.Context, serviceArn *string) ([]types.ScalingPolicy, error) {
 	policiesInput := &applicationautoscaling.DescribeScalingPoliciesInput{
 		ServiceNamespace: "ecs",
 		ResourceId:       serviceArn,
 	}
-	policiesOutput, err := store.autoScaling.DescribeScalingPolicies(context.Background(), policiesInput)
+	policiesOutput, err := store.autoScaling.DescribeScalingPolicies(ctx, policiesInput)
 
 	if err != nil {
 		slog.Warn("failed to run aws api to auto scaling activities", "serviceArn", *serviceArn, "error", err)
@@ -107,12 +110,12 @@ func (store *Store) describeScalingPolicies(serviceArn *string) ([]types.Scaling
 
 // Equivalent to
 // aws application-autoscaling describe-scheduled-actions --service-namespace ecs --resource-id "service/<ClusterName>/<ServiceName>"
-func (store *Store) describeScheduledAction(serviceArn *string) ([]types.ScheduledAction, error) {
+func (store *Store) describeScheduledAction(ctx context.Context, serviceArn *string) ([]types.ScheduledAction, error) {
 	actionsInput := &applicationautoscaling.DescribeScheduledActionsInput{
 		ServiceNamespace: "ecs",
 		ResourceId:       serviceArn,
 	}
-	actionsOutput, err := store.autoScaling.DescribeScheduledActions(context.Background(), actionsInput)
+	actionsOutput, err := store.autoScaling.DescribeScheduledActions(ctx, actionsInput)
 
 	if err != nil {
 		slog.Warn("failed to run aws api to auto scaling scheduled actions", "serviceArn", *serviceArn, "error", err)
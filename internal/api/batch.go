@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// Batches describing at the same time for one Store call
+	batchParallel = 5
+	// Attempts of a throttled batch, SDK retries come on top of these
+	batchMaxAttempts = 3
+	batchRetryDelay  = 500 * time.Millisecond
+)
+
+var throttles = retry.IsErrorThrottles(retry.DefaultThrottles)
+
+// Fetch items in batches of batchSize with at most batchParallel batches in flight.
+// Results keep the order of items no matter which batch finishes first, throttled
+// batches are retried with backoff and the first other error cancels the rest.
+func fetchBatches[I, R any](ctx context.Context, items []I, batchSize int, fetch func(ctx context.Context, batch []I) ([]R, error)) ([]R, error) {
+	batchCount := (len(items) + batchSize - 1) / batchSize
+	batches := make([][]R, batchCount)
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(batchParallel)
+
+	for i := range batches {
+		g.Go(func() error {
+			batch := items[i*batchSize : min((i+1)*batchSize, len(items))]
+			results, err := fetchBatch(ctx, batch, fetch)
+			if err != nil {
+				return err
+			}
+			// Each goroutine owns its index, no lock needed
+			batches[i] = results
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	results := make([]R, 0, len(items))
+	for _, b := range batches {
+		results = append(results, b...)
+	}
+	return results, nil
+}
+
+// Run fetch for one batch, retry when throttled
+func fetchBatch[I, R any](ctx context.Context, batch []I, fetch func(ctx context.Context, batch []I) ([]R, error)) ([]R, error) {
+	delay := batchRetryDelay
+	for attempt := 1; ; attempt++ {
+		results, err := fetch(ctx, batch)
+		if err == nil || attempt == batchMaxAttempts || throttles.IsErrorThrottle(err) != aws.TrueTernary {
+			return results, err
+		}
+		slog.Warn("batch throttled, retry", "attempt", attempt, "delay", delay, "error", err)
+		notifyRetry(ctx, attempt+1, batchMaxAttempts)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-retryAfter(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/smithy-go"
+)
+
+var errThrottled = &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+
+// ECS client listing services in one page and describing them with describe,
+// attempts of each request are counted by its first service
+type fakeEcs struct {
+	ecsAPI
+	arns     []string
+	describe func(ctx context.Context, services []string, attempt int) error
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func newFakeEcs(count int) *fakeEcs {
+	f := &fakeEcs{attempts: map[string]int{}}
+	for i := range count {
+		f.arns = append(f.arns, fmt.Sprintf("arn:aws:ecs:us-east-1:111111:service/big/svc-%02d", i))
+	}
+	return f
+}
+
+func (f *fakeEcs) ListServices(ctx context.Context, input *ecs.ListServicesInput, _ ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	return &ecs.ListServicesOutput{ServiceArns: f.arns}, nil
+}
+
+func (f *fakeEcs) DescribeServices(ctx context.Context, input *ecs.DescribeServicesInput, _ ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	f.mu.Lock()
+	f.attempts[input.Services[0]]++
+	attempt := f.attempts[input.Services[0]]
+	f.mu.Unlock()
+
+	if err := f.describe(ctx, input.Services, attempt); err != nil {
+		return nil, err
+	}
+	output := &ecs.DescribeServicesOutput{}
+	for _, arn := range input.Services {
+		output.Services = append(output.Services, types.Service{ServiceArn: aws.String(arn)})
+	}
+	return output, nil
+}
+
+// Index of the batch of services
+func (f *fakeEcs) batch(services []string) int {
+	return slices.Index(f.arns, services[0]) / 10
+}
+
+// Retry without waiting, delays are recorded
+func noRetryWait(t *testing.T) *[]time.Duration {
+	var mu sync.Mutex
+	delays := []time.Duration{}
+	retryAfter = func(d time.Duration) <-chan time.Time {
+		mu.Lock()
+		delays = append(delays, d)
+		mu.Unlock()
+		return time.After(0)
+	}
+	t.Cleanup(func() { retryAfter = time.After })
+	return &delays
+}
+
+func TestFetchBatchesKeepsOrder(t *testing.T) {
+	noRetryWait(t)
+	f := newFakeEcs(95)
+	// earlier batches finish later, every third batch is throttled once
+	f.describe = func(ctx context.Context, services []string, attempt int) error {
+		i := f.batch(services)
+		time.Sleep(time.Duration(10-i) * time.Millisecond)
+		if i%3 == 0 && attempt == 1 {
+			return errThrottled
+		}
+		return nil
+	}
+	store := &Store{ecs: f}
+
+	// batches notify from their own goroutines
+	var retries atomic.Int32
+	ctx := WithRetryNotify(context.Background(), func(attempt, maxAttempts int) {
+		retries.Add(1)
+	})
+	services, err := store.ListServices(ctx, aws.String("big"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != len(f.arns) {
+		t.Fatalf("Got %d services, Want: %d", len(services), len(f.arns))
+	}
+	for i, s := range services {
+		if *s.ServiceArn != f.arns[i] {
+			t.Fatalf("Got service %s at %d, Want: %s", *s.ServiceArn, i, f.arns[i])
+		}
+	}
+	if retries.Load() != 4 {
+		t.Errorf("Got %d retries, Want: 4 throttled batches retried", retries.Load())
+	}
+}
+
+func TestFetchBatchesThrottled(t *testing.T) {
+	delays := noRetryWait(t)
+	f := newFakeEcs(30)
+	f.describe = func(ctx context.Context, services []string, attempt int) error {
+		if f.batch(services) == 1 {
+			return errThrottled
+		}
+		return nil
+	}
+	store := &Store{ecs: f}
+
+	_, err := store.ListServices(context.Background(), aws.String("big"))
+	if !errors.Is(err, errThrottled) {
+		t.Fatalf("Got error: %v, Want: throttling error", err)
+	}
+	if got := f.attempts[f.arns[10]]; got != batchMaxAttempts {
+		t.Errorf("Got %d attempts, Want: %d", got, batchMaxAttempts)
+	}
+	want := []time.Duration{batchRetryDelay, 2 * batchRetryDelay}
+	if !slices.Equal(*delays, want) {
+		t.Errorf("Got delays %v, Want: %v", *delays, want)
+	}
+}
+
+func TestFetchBatchesCancelOnError(t *testing.T) {
+	noRetryWait(t)
+	f := newFakeEcs(50)
+	errDenied := errors.New("AccessDeniedException")
+	var mu sync.Mutex
+	cancelled := 0
+	f.describe = func(ctx context.Context, services []string, attempt int) error {
+		if f.batch(services) == 0 {
+			return errDenied
+		}
+		// other batches wait until cancelled
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			cancelled++
+			mu.Unlock()
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	}
+	store := &Store{ecs: f}
+
+	_, err := store.ListServices(context.Background(), aws.String("big"))
+	if !errors.Is(err, errDenied) {
+		t.Fatalf("Got error: %v, Want: %v", err, errDenied)
+	}
+	if got := f.attempts[f.arns[0]]; got != 1 {
+		t.Errorf("Got %d attempts, Want: other errors not retried", got)
+	}
+	if cancelled != 4 {
+		t.Errorf("Got %d batches cancelled, Want: 4", cancelled)
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheKind string
+
+const (
+	cacheClusters        cacheKind = "clusters"
+	cacheServices        cacheKind = "services"
+	cacheTasks           cacheKind = "tasks"
+	cacheInstances       cacheKind = "instances"
+	cacheTaskDefinitions cacheKind = "taskDefinitions"
+	cacheIdentity        cacheKind = "identity"
+)
+
+// How long a response is served without refreshing, zero never expires.
+// Task definition revisions are immutable.
+var cacheTTL = map[cacheKind]time.Duration{
+	cacheClusters:        30 * time.Second,
+	cacheServices:        15 * time.Second,
+	cacheTasks:           10 * time.Second,
+	cacheInstances:       30 * time.Second,
+	cacheTaskDefinitions: 0,
+	cacheIdentity:        5 * time.Minute,
+}
+
+type cacheEntry struct {
+	value      any
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// Response cache of Store calls by kind and call arguments
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: map[string]*cacheEntry{}}
+}
+
+type bypassCacheKey struct{}
+
+// Store calls made with the returned context skip cached responses and refill the cache
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func bypassCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// Serve fetch result from cache.
+// Fresh entries are returned as is, stale entries are returned immediately and refreshed
+// in background, missing entries are fetched. Only successful results are cached.
+func cached[T any](ctx context.Context, store *Store, kind cacheKind, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	c := store.cache
+	if c == nil {
+		return fetch(ctx)
+	}
+	key = string(kind) + "/" + key
+
+	if !bypassCache(ctx) {
+		c.mu.Lock()
+		e, ok := c.entries[key]
+		if ok {
+			value := e.value.(T)
+			ttl := cacheTTL[kind]
+			if ttl > 0 && time.Since(e.fetchedAt) > ttl && !e.refreshing {
+				e.refreshing = true
+				go revalidate(ctx, store, c, key, e, fetch)
+			}
+			c.mu.Unlock()
+			return value, nil
+		}
+		c.mu.Unlock()
+	}
+
+	value, err := fetch(ctx)
+	if err == nil {
+		c.mu.Lock()
+		c.entries[key] = &cacheEntry{value: value, fetchedAt: time.Now()}
+		c.mu.Unlock()
+	}
+	return value, err
+}
+
+// Refresh stale entry, it is kept when the refresh fails
+func revalidate[T any](ctx context.Context, store *Store, c *responseCache, key string, e *cacheEntry, fetch func(ctx context.Context) (T, error)) {
+	// Page which asked for the entry may be gone already
+	ctx, cancel := store.withTimeout(context.WithoutCancel(ctx))
+	defer cancel()
+
+	value, err := fetch(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.refreshing = false
+	if err != nil {
+		slog.Warn("failed to refresh cache", "key", key, "error", err)
+		return
+	}
+	if c.entries[key] == e {
+		c.entries[key] = &cacheEntry{value: value, fetchedAt: time.Now()}
+	}
+}
+
+// Drop cached responses of given kinds, all kinds but task definitions when none given
+func (c *responseCache) invalidate(kinds ...cacheKind) {
+	if c == nil {
+		return
+	}
+	if len(kinds) == 0 {
+		kinds = []cacheKind{cacheClusters, cacheServices, cacheTasks, cacheInstances, cacheIdentity}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		for _, kind := range kinds {
+			if strings.HasPrefix(key, string(kind)+"/") {
+				delete(c.entries, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Fetch counting calls, each call returns the next count
+type countingFetch struct {
+	calls int
+	err   error
+	// closed by every call when non nil
+	done chan struct{}
+}
+
+func (f *countingFetch) fetch(ctx context.Context) (int, error) {
+	f.calls++
+	if f.done != nil {
+		defer close(f.done)
+	}
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.calls, nil
+}
+
+// Make the cached entry of key older than its TTL
+func expire(store *Store, kind cacheKind, key string) {
+	store.cache.mu.Lock()
+	defer store.cache.mu.Unlock()
+	store.cache.entries[string(kind)+"/"+key].fetchedAt = time.Now().Add(-2 * cacheTTL[kind])
+}
+
+func waitRefreshed(t *testing.T, store *Store, kind cacheKind, key string) *cacheEntry {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		store.cache.mu.Lock()
+		e := store.cache.entries[string(kind)+"/"+key]
+		refreshing := e.refreshing
+		store.cache.mu.Unlock()
+		if !refreshing {
+			return e
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("cache entry not refreshed before deadline")
+	return nil
+}
+
+func TestCached(t *testing.T) {
+	store := &Store{cache: newResponseCache()}
+	ctx := context.Background()
+	f := &countingFetch{}
+
+	if got, _ := cached(ctx, store, cacheServices, "prod", f.fetch); got != 1 {
+		t.Fatalf("Got %d, Want: fetched 1", got)
+	}
+	if got, _ := cached(ctx, store, cacheServices, "prod", f.fetch); got != 1 || f.calls != 1 {
+		t.Errorf("Got %d after %d calls, Want: fresh entry served", got, f.calls)
+	}
+	if got, _ := cached(ctx, store, cacheServices, "staging", f.fetch); got != 2 {
+		t.Errorf("Got %d, Want: other arguments fetched", got)
+	}
+	if got, _ := cached(WithoutCache(ctx), store, cacheServices, "prod", f.fetch); got != 3 {
+		t.Errorf("Got %d, Want: bypassing cache fetches", got)
+	}
+	if got, _ := cached(ctx, store, cacheServices, "prod", f.fetch); got != 3 {
+		t.Errorf("Got %d, Want: cache refilled by bypassing call", got)
+	}
+
+	// errors are not cached
+	f.err = errors.New("AccessDeniedException")
+	if _, err := cached(ctx, store, cacheServices, "dev", f.fetch); err == nil {
+		t.Fatal("Want: fetch error")
+	}
+	f.err = nil
+	if _, err := cached(ctx, store, cacheServices, "dev", f.fetch); err != nil {
+		t.Errorf("Got error: %v, Want: fetched again", err)
+	}
+}
+
+func TestCachedRevalidate(t *testing.T) {
+	store := &Store{cache: newResponseCache()}
+	ctx := context.Background()
+	f := &countingFetch{}
+	cached(ctx, store, cacheServices, "prod", f.fetch)
+
+	// stale entry is served while it is refreshed in background
+	expire(store, cacheServices, "prod")
+	f.done = make(chan struct{})
+	if got, _ := cached(ctx, store, cacheServices, "prod", f.fetch); got != 1 {
+		t.Errorf("Got %d, Want: stale entry served", got)
+	}
+	<-f.done
+	if e := waitRefreshed(t, store, cacheServices, "prod"); e.value.(int) != 2 || time.Since(e.fetchedAt) > time.Second {
+		t.Errorf("Got %v fetched at %s, Want: refreshed entry", e.value, e.fetchedAt)
+	}
+
+	// failed refresh keeps the stale entry and a later call refreshes again
+	expire(store, cacheServices, "prod")
+	f.err = errors.New("ThrottlingException")
+	f.done = make(chan struct{})
+	if got, _ := cached(ctx, store, cacheServices, "prod", f.fetch); got != 2 {
+		t.Errorf("Got %d, Want: stale entry served", got)
+	}
+	<-f.done
+	if e := waitRefreshed(t, store, cacheServices, "prod"); e.value.(int) != 2 {
+		t.Errorf("Got %v, Want: entry kept after failed refresh", e.value)
+	}
+	f.err = nil
+	f.done = make(chan struct{})
+	cached(ctx, store, cacheServices, "prod", f.fetch)
+	<-f.done
+	if e := waitRefreshed(t, store, cacheServices, "prod"); e.value.(int) != 4 {
+		t.Errorf("Got %v, Want: refreshed on next call", e.value)
+	}
+
+	// a cancelled page does not cancel the refresh
+	expire(store, cacheServices, "prod")
+	f.done = make(chan struct{})
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	cached(cancelled, store, cacheServices, "prod", func(ctx context.Context) (int, error) {
+		if err := ctx.Err(); err != nil {
+			close(f.done)
+			return 0, err
+		}
+		return f.fetch(ctx)
+	})
+	<-f.done
+	if e := waitRefreshed(t, store, cacheServices, "prod"); e.value.(int) != 5 {
+		t.Errorf("Got %v, Want: refreshed after page was cancelled", e.value)
+	}
+
+	// task definition revisions never expire
+	f.done = nil
+	cached(ctx, store, cacheTaskDefinitions, "web:1", f.fetch)
+	expire(store, cacheTaskDefinitions, "web:1")
+	calls := f.calls
+	cached(ctx, store, cacheTaskDefinitions, "web:1", f.fetch)
+	if f.calls != calls {
+		t.Error("Want: task definition served without refresh")
+	}
+}
+
+func TestCacheInvalidatedByMutations(t *testing.T) {
+	clusterArn := "arn:aws:ecs:us-east-1:111111:cluster/cached"
+	fixtures := &Fixtures{
+		Region: "us-east-1",
+		Services: []types.Service{{
+			ServiceName:  aws.String("web"),
+			ServiceArn:   aws.String("arn:aws:ecs:us-east-1:111111:service/cached/web"),
+			ClusterArn:   aws.String(clusterArn),
+			DesiredCount: 1,
+		}},
+		Tasks: []types.Task{{
+			TaskArn:       aws.String("arn:aws:ecs:us-east-1:111111:task/cached/0001"),
+			ClusterArn:    aws.String(clusterArn),
+			Group:         aws.String("service:web"),
+			DesiredStatus: aws.String(string(types.DesiredStatusRunning)),
+		}},
+	}
+	store := NewFixtureStore(fixtures)
+	ctx := context.Background()
+
+	desiredCount := func() int32 {
+		t.Helper()
+		services, err := store.ListServices(ctx, aws.String("cached"))
+		if err != nil || len(services) != 1 {
+			t.Fatalf("Got %d services, error: %v", len(services), err)
+		}
+		return services[0].DesiredCount
+	}
+	desiredCount()
+	fixtures.Services[0].DesiredCount = 3
+	if got := desiredCount(); got != 1 {
+		t.Fatalf("Got desired count %d, Want: 1 cached", got)
+	}
+	if _, err := store.UpdateService(ctx, &ecs.UpdateServiceInput{Cluster: aws.String("cached"), Service: aws.String("web"), DesiredCount: aws.Int32(2)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := desiredCount(); got != 2 {
+		t.Errorf("Got desired count %d, Want: 2 after update", got)
+	}
+
+	runningTasks := func() int {
+		t.Helper()
+		tasks, _, err := store.ListTasks(ctx, aws.String("cached"), aws.String("web"), types.DesiredStatusRunning)
+		if err != nil {
+			t.Fatal(err)
+		}
+		running := 0
+		for _, task := range tasks {
+			if aws.ToString(task.DesiredStatus) == string(types.DesiredStatusRunning) {
+				running++
+			}
+		}
+		return running
+	}
+	if got := runningTasks(); got != 1 {
+		t.Fatalf("Got %d running tasks, Want: 1", got)
+	}
+	if err := store.StopTask(ctx, &ecs.StopTaskInput{Cluster: aws.String("cached"), Task: fixtures.Tasks[0].TaskArn}); err != nil {
+		t.Fatal(err)
+	}
+	if got := runningTasks(); got != 0 {
+		t.Errorf("Got %d running tasks, Want: 0 after stop", got)
+	}
+
+	// task definitions are kept
+	cached(ctx, store, cacheTaskDefinitions, "web:1", func(ctx context.Context) (string, error) { return "web:1", nil })
+	store.cache.invalidate()
+	if _, ok := store.cache.entries[string(cacheTaskDefinitions)+"/web:1"]; !ok {
+		t.Error("Want: task definition kept after invalidate")
+	}
+}
@@ -3,39 +3,37 @@ package api
 import (
 	"context"
 	"log/slog"
-	"math"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
-	"golang.org/x/sync/errgroup"
 )
 
 // Equivalent to
 // aws ecs list-clusters
 // aws ecs describe-clusters --clusters ${clusters}
-func (store *Store) ListClusters() ([]types.Cluster, error) {
+func (store *Store) ListClusters(ctx context.Context) ([]types.Cluster, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	return cached(ctx, store, cacheClusters, "", store.listClusters)
+}
+
+func (store *Store) listClusters(ctx context.Context) ([]types.Cluster, error) {
 	batchSize := 100
 	limit := int32(batchSize)
-	clusterARNs := []string{}
-	params := &ecs.ListClustersInput{
-		MaxResults: &limit,
-	}
-
-	for {
-		clustersOutput, err := store.ecs.ListClusters(context.Background(), params)
+	clusterARNs, listErr := paginate(ctx, func(ctx context.Context, token *string) ([]string, *string, error) {
+		clustersOutput, err := store.ecs.ListClusters(ctx, &ecs.ListClustersInput{
+			MaxResults: &limit,
+			NextToken:  token,
+		})
 		if err != nil {
 			slog.Warn("failed to run aws api to list clusters", "error", err)
-			if len(clusterARNs) == 0 {
-				return []types.Cluster{}, err
-			}
-			continue
-		}
-		clusterARNs = append(clusterARNs, clustersOutput.ClusterArns...)
-		if clustersOutput.NextToken != nil {
-			params.NextToken = clustersOutput.NextToken
-		} else {
-			break
+			return nil, nil, err
 		}
+		return clustersOutput.ClusterArns, clustersOutput.NextToken, nil
+	})
+	if listErr != nil && len(clusterARNs) == 0 {
+		return []types.Cluster{}, listErr
 	}
 
 	include := []types.ClusterField{
@@ -46,39 +44,22 @@ func (store *Store) ListClusters() ([]types.Cluster, error) {
 		types.ClusterFieldTags,
 	}
 
-	results := []types.Cluster{}
-	g := new(errgroup.Group)
-
-	clusterCount := len(clusterARNs)
-	loopCount := clusterCount / batchSize
-
-	if clusterCount%batchSize == 0 {
-		loopCount = loopCount - 1
-	}
-
-	for i := 0; i <= loopCount; i++ {
-		i := i
-		g.Go(func() error {
-			clusters := clusterARNs[i*batchSize : int(math.Min(float64((i+1)*batchSize), float64(clusterCount)))]
-
-			// If describe more than 100, InvalidParameterException: Clusters cannot have more than 100 elements
-			describeClusterOutput, err := store.ecs.DescribeClusters(context.Background(), &ecs.DescribeClustersInput{
-				Clusters: clusters,
-				Include:  include,
-			})
-			if err != nil {
-				slog.Warn("failed to run aws api to describe clusters", "i times loop", i, "error", err)
-				return err
-			}
-
-			results = append(results, describeClusterOutput.Clusters...)
-			return nil
+	// If describe more than 100, InvalidParameterException: Clusters cannot have more than 100 elements
+	results, err := fetchBatches(ctx, clusterARNs, batchSize, func(ctx context.Context, clusters []string) ([]types.Cluster, error) {
+		describeClusterOutput, err := store.ecs.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+			Clusters: clusters,
+			Include:  include,
 		})
-	}
-
-	if err := g.Wait(); err != nil {
+		if err != nil {
+			slog.Warn("failed to run aws api to describe clusters", "error", err)
+			return nil, err
+		}
+		return describeClusterOutput.Clusters, nil
+	})
+	if err != nil {
 		return []types.Cluster{}, err
 	}
 
-	return results, nil
+	// Partial list error, if any, tells the UI not all clusters are shown
+	return results, listErr
 }
@@ -3,25 +3,38 @@ package api
 import (
 	"bufio"
 	"context"
+	"errors"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
 	"github.com/aws/aws-sdk-go-v2/service/account"
-	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ssooidcTypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+	"github.com/aws/smithy-go"
 	regionsLib "github.com/keidarcy/aws-regions/v3"
 )
 
 // SwitchAwsConfig switches to a different AWS profile and reinitializes clients
-func (store *Store) SwitchAwsConfig(profile string, region string) error {
+func (store *Store) SwitchAwsConfig(ctx context.Context, profile string, region string) error {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	if store.IsFixture() {
+		return errFixtureUnsupported
+	}
+	// Switch the store of the current profile and region, callers pick other stores again afterwards
+	store = store.Home()
 	os.Setenv("AWS_PROFILE", profile)
 	os.Setenv("AWS_REGION", region)
 
 	// Load new configuration with the updated profile
-	cfg, err := config.LoadDefaultConfig(context.Background())
+	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		slog.Error("failed to load aws SDK config with new profile", "profile", profile, "error", err)
 		return err
@@ -31,17 +44,97 @@ func (store *Store) SwitchAwsConfig(profile string, region string) error {
 	store.Config = &cfg
 
 	// Reinitialize all clients with new configuration
-	store.ecs = ecs.NewFromConfig(cfg)
+	store.ecs = store.newEcsClient(cfg)
 	store.cloudwatch = nil     // Will be lazy-loaded with new config
 	store.cloudwatchlogs = nil // Will be lazy-loaded with new config
 	store.ssm = nil            // Will be lazy-loaded with new config
 	store.autoScaling = nil    // Will be lazy-loaded with new config
 	store.account = nil
+	store.iam = nil
+	store.sts = nil
+	store.cache.invalidate()
+	store.profile = profile
+	store.regionalMu.Lock()
+	store.regional = nil
+	store.regionalMu.Unlock()
+	store.profilesMu.Lock()
+	store.profiles = nil
+	store.profilesMu.Unlock()
 
 	slog.Info("switched AWS profile", slog.String("AWS_PROFILE", profile), slog.String("AWS_REGION", region))
 	return nil
 }
 
+// Error codes of expired session credentials
+var expiredTokenCodes = []string{"ExpiredToken", "ExpiredTokenException", "UnauthorizedException"}
+
+// ExpiredCredentials reports whether err comes from an expired or missing SSO session
+// or expired session credentials, and the profile to log in with again
+func (store *Store) ExpiredCredentials(err error) (profile string, expired bool) {
+	if err == nil {
+		return "", false
+	}
+	profile = store.Profile()
+	var profileErr *ProfileError
+	if errors.As(err, &profileErr) {
+		profile = profileErr.Profile
+	}
+
+	var tokenErr *ssocreds.InvalidTokenError
+	if errors.As(err, &tokenErr) {
+		return profile, true
+	}
+	// Refreshing the token of a sso-session profile failed
+	var expiredErr *ssooidcTypes.ExpiredTokenException
+	var grantErr *ssooidcTypes.InvalidGrantException
+	var clientErr *ssooidcTypes.InvalidClientException
+	var unauthorizedErr *ssooidcTypes.UnauthorizedClientException
+	if errors.As(err, &expiredErr) || errors.As(err, &grantErr) || errors.As(err, &clientErr) || errors.As(err, &unauthorizedErr) {
+		return profile, true
+	}
+	if isMissingSSOToken(err) {
+		return profile, true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		for _, code := range expiredTokenCodes {
+			if apiErr.ErrorCode() == code {
+				return profile, true
+			}
+		}
+	}
+	return profile, false
+}
+
+// Whether the cached token of a sso-session profile is missing, e.g. after aws sso logout
+func isMissingSSOToken(err error) bool {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) || !errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+	tokenPath, err := ssocreds.StandardCachedTokenFilepath("")
+	if err != nil {
+		return false
+	}
+	return filepath.Dir(pathErr.Path) == filepath.Dir(tokenPath)
+}
+
+// InvalidateCredentials makes the next call of every profile load credentials again, e.g. after a SSO login
+func (store *Store) InvalidateCredentials() {
+	home := store.Home()
+	stores := []*Store{home}
+	home.profilesMu.Lock()
+	for _, s := range home.profiles {
+		stores = append(stores, s)
+	}
+	home.profilesMu.Unlock()
+	for _, s := range stores {
+		if cache, ok := s.Credentials.(*aws.CredentialsCache); ok {
+			cache.Invalidate()
+		}
+	}
+}
+
 // Profile summarizes one named profile from local AWS config and credentials files.
 type Profile struct {
 	Name          string
@@ -250,10 +343,13 @@ type Region struct {
 	Enabled string
 }
 
-func (store *Store) ListRegions() ([]Region, error) {
+func (store *Store) ListRegions(ctx context.Context) ([]Region, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	store.initAccountClient()
 	limit := int32(50)
-	regionsOutput, err := store.account.ListRegions(context.Background(), &account.ListRegionsInput{MaxResults: &limit})
+	regionsOutput, err := store.account.ListRegions(ctx, &account.ListRegionsInput{MaxResults: &limit})
 	if err != nil {
 		slog.Warn("failed to run aws api list regions", "error", err)
 		return nil, err
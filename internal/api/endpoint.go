@@ -0,0 +1,49 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/keidarcy/e1s/internal/utils"
+)
+
+// Endpoint overrides, e.g. LocalStack or VPC interface endpoints. Empty uses AWS endpoints.
+type Endpoints struct {
+	// Endpoint of every service without its own override
+	URL string
+	// Endpoints by aws cli service name: ecs, logs, cloudwatch, ssm, application-autoscaling
+	Services map[string]string
+}
+
+// Endpoint URL of service, empty when not overridden
+func (e Endpoints) For(service string) string {
+	if url := e.Services[service]; url != "" {
+		return url
+	}
+	return e.URL
+}
+
+// Endpoint override of service, nil keeps the endpoint resolved by the SDK
+func (store *Store) endpoint(service string) *string {
+	if url := store.Endpoints.For(service); url != "" {
+		return aws.String(url)
+	}
+	return nil
+}
+
+func (store *Store) newEcsClient(cfg aws.Config) *ecs.Client {
+	return ecs.NewFromConfig(cfg, func(o *ecs.Options) {
+		if e := store.endpoint("ecs"); e != nil {
+			o.BaseEndpoint = e
+		}
+	})
+}
+
+// Endpoint of session-manager-plugin sessions in region of partition
+func (store *Store) ssmEndpoint(region string, partition string) string {
+	if e := store.endpoint("ssm"); e != nil {
+		return *e
+	}
+	return fmt.Sprintf("https://ssm.%v.%s", region, utils.PartitionDNSSuffix(partition))
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/utils"
+)
+
+const defaultFixtureRegion = "us-east-1"
+
+//go:embed fixtures/demo.json
+var demoFixtures []byte
+
+var errFixtureUnsupported = errors.New("not supported with fixtures")
+
+// Fixtures is an offline snapshot of ECS resources served instead of AWS.
+// Resources use the same shape as the AWS SDK types, so the JSON shown by
+// describe pages can be pasted into a snapshot as is.
+type Fixtures struct {
+	Region             string
+	Regions            []string
+	Clusters           []types.Cluster
+	Services           []types.Service
+	Tasks              []types.Task
+	TaskDefinitions    []types.TaskDefinition
+	ContainerInstances []types.ContainerInstance
+	ServiceDeployments []types.ServiceDeployment
+	ServiceRevisions   []types.ServiceRevision
+	// Log events by log group name and log stream name
+	Logs map[string]map[string][]cloudwatchlogsTypes.OutputLogEvent
+	// Metrics by "${cluster}/${service}", "${cluster}/${taskId}" and "${cluster}/${taskId}/${container}"
+	Metrics map[string]MetricsData
+	// Service auto scaling by resource id "service/${cluster}/${service}"
+	AutoScaling map[string]AutoScalingData
+}
+
+// Load fixtures from a JSON or YAML snapshot file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yml" || ext == ".yaml" {
+		data, err = utils.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fixtures %s: %w", path, err)
+		}
+	}
+	return parseFixtures(data)
+}
+
+// Load fixtures bundled for demo mode
+func DemoFixtures() (*Fixtures, error) {
+	return parseFixtures(demoFixtures)
+}
+
+func parseFixtures(data []byte) (*Fixtures, error) {
+	fixtures := &Fixtures{}
+	if err := json.Unmarshal(data, fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	if fixtures.Region == "" {
+		fixtures.Region = defaultFixtureRegion
+	}
+	if len(fixtures.Regions) == 0 {
+		fixtures.Regions = []string{fixtures.Region}
+	}
+	return fixtures, nil
+}
+
+// NewFixtureStore returns a store serving all API calls from fixtures
+func NewFixtureStore(fixtures *Fixtures) *Store {
+	slog.Info("load fixtures", slog.String("AWS_REGION", fixtures.Region), slog.Int("clusters", len(fixtures.Clusters)))
+	return newFixtureRegionStore(newFixtureBackend(fixtures), fixtures.Region)
+}
+
+// Fixture store of one region, it lists only clusters whose ARN is in region
+func newFixtureRegionStore(backend *fixtureBackend, region string) *Store {
+	return &Store{
+		Config:         &aws.Config{Region: region},
+		ecs:            &fixtureEcs{backend, region},
+		cloudwatch:     &fixtureCloudwatch{backend},
+		cloudwatchlogs: &fixtureCloudwatchlogs{backend},
+		autoScaling:    &fixtureAutoScaling{backend},
+		ssm:            &fixtureSsm{backend},
+		account:        &fixtureAccount{backend},
+		iam:            &fixtureIam{backend},
+		sts:            &fixtureSts{backend},
+		fixtures:       backend.Fixtures,
+		cache:          newResponseCache(),
+	}
+}
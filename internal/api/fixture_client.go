@@ -0,0 +1,833 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/account"
+	accountTypes "github.com/aws/aws-sdk-go-v2/service/account/types"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/keidarcy/e1s/internal/utils"
+)
+
+// In memory backend shared by all fixture clients.
+// Mutations (update service, stop task, register task definition) only change the snapshot in memory.
+type fixtureBackend struct {
+	mu sync.RWMutex
+	*Fixtures
+	// Logs Insights query results by query id
+	queries map[string]*cloudwatchlogs.GetQueryResultsOutput
+}
+
+func newFixtureBackend(fixtures *Fixtures) *fixtureBackend {
+	return &fixtureBackend{Fixtures: fixtures}
+}
+
+// Match a request reference (name or ARN) against a resource ARN
+func matchArn(ref *string, arn *string) bool {
+	if ref == nil || arn == nil {
+		return false
+	}
+	return *ref == *arn || *ref == utils.ArnToName(arn)
+}
+
+// ECS defaults to the "default" cluster when none is given
+func matchCluster(ref *string, clusterArn *string) bool {
+	if ref == nil {
+		ref = aws.String("default")
+	}
+	return matchArn(ref, clusterArn)
+}
+
+// Paginate items the same way AWS list APIs do, the token is the next offset
+func fixturePage(items []string, maxResults *int32, nextToken *string, defaultLimit int) ([]string, *string, error) {
+	start := 0
+	if nextToken != nil {
+		n, err := strconv.Atoi(*nextToken)
+		if err != nil || n < 0 || n > len(items) {
+			return nil, nil, fmt.Errorf("InvalidParameterException: invalid next token %q", *nextToken)
+		}
+		start = n
+	}
+	limit := defaultLimit
+	if maxResults != nil && *maxResults > 0 {
+		limit = int(*maxResults)
+	}
+	end := start + limit
+	if end >= len(items) {
+		return items[start:], nil, nil
+	}
+	return items[start:end], aws.String(strconv.Itoa(end)), nil
+}
+
+func checkBatchSize(name string, size, max int) error {
+	if size == 0 {
+		return fmt.Errorf("InvalidParameterException: %s cannot be empty", name)
+	}
+	if size > max {
+		return fmt.Errorf("InvalidParameterException: %s cannot have more than %d elements", name, max)
+	}
+	return nil
+}
+
+type fixtureEcs struct {
+	*fixtureBackend
+	region string
+}
+
+func (f *fixtureEcs) ListClusters(_ context.Context, input *ecs.ListClustersInput, _ ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	arns := []string{}
+	for _, c := range f.Clusters {
+		if region := utils.ArnToRegion(c.ClusterArn); region != "" && f.region != "" && region != f.region {
+			continue
+		}
+		arns = append(arns, *c.ClusterArn)
+	}
+	page, next, err := fixturePage(arns, input.MaxResults, input.NextToken, 100)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListClustersOutput{ClusterArns: page, NextToken: next}, nil
+}
+
+func (f *fixtureEcs) DescribeClusters(_ context.Context, input *ecs.DescribeClustersInput, _ ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error) {
+	if err := checkBatchSize("Clusters", len(input.Clusters), 100); err != nil {
+		return nil, err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &ecs.DescribeClustersOutput{}
+	for _, ref := range input.Clusters {
+		for _, c := range f.Clusters {
+			if matchArn(&ref, c.ClusterArn) {
+				output.Clusters = append(output.Clusters, c)
+				break
+			}
+		}
+	}
+	return output, nil
+}
+
+func (f *fixtureEcs) ListServices(_ context.Context, input *ecs.ListServicesInput, _ ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	arns := []string{}
+	for _, s := range f.Services {
+		if matchCluster(input.Cluster, s.ClusterArn) {
+			arns = append(arns, *s.ServiceArn)
+		}
+	}
+	page, next, err := fixturePage(arns, input.MaxResults, input.NextToken, 10)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListServicesOutput{ServiceArns: page, NextToken: next}, nil
+}
+
+func (f *fixtureEcs) DescribeServices(_ context.Context, input *ecs.DescribeServicesInput, _ ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	if err := checkBatchSize("Services", len(input.Services), 10); err != nil {
+		return nil, err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &ecs.DescribeServicesOutput{}
+	for _, ref := range input.Services {
+		for _, s := range f.Services {
+			if matchCluster(input.Cluster, s.ClusterArn) && matchArn(&ref, s.ServiceArn) {
+				output.Services = append(output.Services, s)
+				break
+			}
+		}
+	}
+	return output, nil
+}
+
+func (f *fixtureEcs) UpdateService(_ context.Context, input *ecs.UpdateServiceInput, _ ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, s := range f.Services {
+		if !matchCluster(input.Cluster, s.ClusterArn) || !matchArn(input.Service, s.ServiceArn) {
+			continue
+		}
+		if input.DesiredCount != nil {
+			s.DesiredCount = *input.DesiredCount
+		}
+		if input.EnableExecuteCommand != nil {
+			s.EnableExecuteCommand = *input.EnableExecuteCommand
+		}
+		if input.TaskDefinition != nil {
+			td, ok := f.findTaskDefinition(*input.TaskDefinition)
+			if !ok {
+				return nil, fmt.Errorf("ClientException: unable to describe task definition %s", *input.TaskDefinition)
+			}
+			s.TaskDefinition = td.TaskDefinitionArn
+		}
+		f.Services[i] = s
+		return &ecs.UpdateServiceOutput{Service: &s}, nil
+	}
+	return nil, fmt.Errorf("ServiceNotFoundException: service %s not found", aws.ToString(input.Service))
+}
+
+func (f *fixtureEcs) ListServiceDeployments(_ context.Context, input *ecs.ListServiceDeploymentsInput, _ ...func(*ecs.Options)) (*ecs.ListServiceDeploymentsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &ecs.ListServiceDeploymentsOutput{}
+	for _, d := range f.ServiceDeployments {
+		if !matchCluster(input.Cluster, d.ClusterArn) || !matchArn(input.Service, d.ServiceArn) {
+			continue
+		}
+		output.ServiceDeployments = append(output.ServiceDeployments, types.ServiceDeploymentBrief{
+			ClusterArn:           d.ClusterArn,
+			ServiceArn:           d.ServiceArn,
+			ServiceDeploymentArn: d.ServiceDeploymentArn,
+			CreatedAt:            d.CreatedAt,
+			StartedAt:            d.StartedAt,
+			FinishedAt:           d.FinishedAt,
+			Status:               d.Status,
+			StatusReason:         d.StatusReason,
+		})
+	}
+	return output, nil
+}
+
+func (f *fixtureEcs) DescribeServiceDeployments(_ context.Context, input *ecs.DescribeServiceDeploymentsInput, _ ...func(*ecs.Options)) (*ecs.DescribeServiceDeploymentsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &ecs.DescribeServiceDeploymentsOutput{}
+	for _, arn := range input.ServiceDeploymentArns {
+		for _, d := range f.ServiceDeployments {
+			if arn == aws.ToString(d.ServiceDeploymentArn) {
+				output.ServiceDeployments = append(output.ServiceDeployments, d)
+				break
+			}
+		}
+	}
+	return output, nil
+}
+
+func (f *fixtureEcs) StopServiceDeployment(_ context.Context, input *ecs.StopServiceDeploymentInput, _ ...func(*ecs.Options)) (*ecs.StopServiceDeploymentOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, d := range f.ServiceDeployments {
+		if aws.ToString(input.ServiceDeploymentArn) == aws.ToString(d.ServiceDeploymentArn) {
+			f.ServiceDeployments[i].Status = types.ServiceDeploymentStatusRollbackRequested
+			return &ecs.StopServiceDeploymentOutput{ServiceDeploymentArn: d.ServiceDeploymentArn}, nil
+		}
+	}
+	return nil, fmt.Errorf("ServiceDeploymentNotFoundException: %s", aws.ToString(input.ServiceDeploymentArn))
+}
+
+func (f *fixtureEcs) DescribeServiceRevisions(_ context.Context, input *ecs.DescribeServiceRevisionsInput, _ ...func(*ecs.Options)) (*ecs.DescribeServiceRevisionsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &ecs.DescribeServiceRevisionsOutput{}
+	for _, arn := range input.ServiceRevisionArns {
+		for _, r := range f.ServiceRevisions {
+			if arn == aws.ToString(r.ServiceRevisionArn) {
+				output.ServiceRevisions = append(output.ServiceRevisions, r)
+				break
+			}
+		}
+	}
+	if len(output.ServiceRevisions) == 0 {
+		return nil, fmt.Errorf("InvalidParameterException: service revision not found")
+	}
+	return output, nil
+}
+
+func (f *fixtureEcs) ListTasks(_ context.Context, input *ecs.ListTasksInput, _ ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	status := input.DesiredStatus
+	if status == "" {
+		status = types.DesiredStatusRunning
+	}
+	arns := []string{}
+	for _, t := range f.Tasks {
+		if !matchCluster(input.Cluster, t.ClusterArn) {
+			continue
+		}
+		if input.ServiceName != nil && *input.ServiceName != utils.GetServiceByTaskGroup(t.Group) {
+			continue
+		}
+		if aws.ToString(t.DesiredStatus) != string(status) {
+			continue
+		}
+		arns = append(arns, *t.TaskArn)
+	}
+	page, next, err := fixturePage(arns, input.MaxResults, input.NextToken, 100)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListTasksOutput{TaskArns: page, NextToken: next}, nil
+}
+
+func (f *fixtureEcs) DescribeTasks(_ context.Context, input *ecs.DescribeTasksInput, _ ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	if err := checkBatchSize("Tasks", len(input.Tasks), 100); err != nil {
+		return nil, err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &ecs.DescribeTasksOutput{}
+	for _, ref := range input.Tasks {
+		for _, t := range f.Tasks {
+			if matchCluster(input.Cluster, t.ClusterArn) && matchArn(&ref, t.TaskArn) {
+				output.Tasks = append(output.Tasks, t)
+				break
+			}
+		}
+	}
+	return output, nil
+}
+
+func (f *fixtureEcs) StopTask(_ context.Context, input *ecs.StopTaskInput, _ ...func(*ecs.Options)) (*ecs.StopTaskOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, t := range f.Tasks {
+		if !matchCluster(input.Cluster, t.ClusterArn) || !matchArn(input.Task, t.TaskArn) {
+			continue
+		}
+		now := time.Now()
+		t.DesiredStatus = aws.String(string(types.DesiredStatusStopped))
+		t.LastStatus = aws.String(string(types.DesiredStatusStopped))
+		t.StopCode = types.TaskStopCodeUserInitiated
+		t.StoppedReason = aws.String("Task stopped by user")
+		t.StoppingAt = &now
+		t.StoppedAt = &now
+		f.Tasks[i] = t
+		return &ecs.StopTaskOutput{Task: &t}, nil
+	}
+	return nil, fmt.Errorf("InvalidParameterException: task %s not found", aws.ToString(input.Task))
+}
+
+// Find a task definition by ARN, "family:revision" or family (latest active revision)
+func (f *fixtureBackend) findTaskDefinition(ref string) (types.TaskDefinition, bool) {
+	found := false
+	var latest types.TaskDefinition
+	for _, td := range f.TaskDefinitions {
+		name := utils.ArnToName(td.TaskDefinitionArn)
+		if ref == aws.ToString(td.TaskDefinitionArn) || ref == name {
+			return td, true
+		}
+		if ref == aws.ToString(td.Family) && (!found || td.Revision > latest.Revision) {
+			latest = td
+			found = true
+		}
+	}
+	return latest, found
+}
+
+func (f *fixtureEcs) DescribeTaskDefinition(_ context.Context, input *ecs.DescribeTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	td, ok := f.findTaskDefinition(aws.ToString(input.TaskDefinition))
+	if !ok {
+		return nil, fmt.Errorf("ClientException: unable to describe task definition %s", aws.ToString(input.TaskDefinition))
+	}
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: &td}, nil
+}
+
+func (f *fixtureEcs) ListTaskDefinitions(_ context.Context, input *ecs.ListTaskDefinitionsInput, _ ...func(*ecs.Options)) (*ecs.ListTaskDefinitionsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	tds := []types.TaskDefinition{}
+	for _, td := range f.TaskDefinitions {
+		if input.FamilyPrefix == nil || strings.HasPrefix(aws.ToString(td.Family), *input.FamilyPrefix) {
+			tds = append(tds, td)
+		}
+	}
+	sort.SliceStable(tds, func(i, j int) bool {
+		if aws.ToString(tds[i].Family) != aws.ToString(tds[j].Family) {
+			return aws.ToString(tds[i].Family) < aws.ToString(tds[j].Family)
+		}
+		if input.Sort == types.SortOrderDesc {
+			return tds[i].Revision > tds[j].Revision
+		}
+		return tds[i].Revision < tds[j].Revision
+	})
+	arns := []string{}
+	for _, td := range tds {
+		arns = append(arns, *td.TaskDefinitionArn)
+	}
+	page, next, err := fixturePage(arns, input.MaxResults, input.NextToken, 100)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListTaskDefinitionsOutput{TaskDefinitionArns: page, NextToken: next}, nil
+}
+
+func (f *fixtureEcs) ListTaskDefinitionFamilies(_ context.Context, input *ecs.ListTaskDefinitionFamiliesInput, _ ...func(*ecs.Options)) (*ecs.ListTaskDefinitionFamiliesOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	seen := map[string]bool{}
+	families := []string{}
+	for _, td := range f.TaskDefinitions {
+		family := aws.ToString(td.Family)
+		if seen[family] {
+			continue
+		}
+		if input.FamilyPrefix != nil && !strings.HasPrefix(family, *input.FamilyPrefix) {
+			continue
+		}
+		seen[family] = true
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	page, next, err := fixturePage(families, input.MaxResults, input.NextToken, 100)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListTaskDefinitionFamiliesOutput{Families: page, NextToken: next}, nil
+}
+
+func (f *fixtureEcs) RegisterTaskDefinition(_ context.Context, input *ecs.RegisterTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.RegisterTaskDefinitionOutput, error) {
+	if input.Family == nil || *input.Family == "" {
+		return nil, fmt.Errorf("ClientException: family is required")
+	}
+	// Input and task definition share field names
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	td := types.TaskDefinition{}
+	if err := json.Unmarshal(raw, &td); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	revision := int32(1)
+	arnPrefix := ""
+	if latest, ok := f.findTaskDefinition(*input.Family); ok {
+		revision = latest.Revision + 1
+		arnPrefix, _, _ = strings.Cut(*latest.TaskDefinitionArn, ":task-definition/")
+	}
+	if arnPrefix == "" {
+		arnPrefix = fmt.Sprintf("arn:aws:ecs:%s:000000000000", f.Region)
+	}
+	now := time.Now()
+	td.Revision = revision
+	td.TaskDefinitionArn = aws.String(fmt.Sprintf("%s:task-definition/%s:%d", arnPrefix, *input.Family, revision))
+	td.Status = types.TaskDefinitionStatusActive
+	td.RegisteredAt = &now
+	f.TaskDefinitions = append(f.TaskDefinitions, td)
+	return &ecs.RegisterTaskDefinitionOutput{TaskDefinition: &td}, nil
+}
+
+func (f *fixtureEcs) ListContainerInstances(_ context.Context, input *ecs.ListContainerInstancesInput, _ ...func(*ecs.Options)) (*ecs.ListContainerInstancesOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	arns := []string{}
+	for _, i := range f.ContainerInstances {
+		// container instance ARN: arn:aws:ecs:region:account:container-instance/${cluster}/${id}
+		parts := strings.Split(aws.ToString(i.ContainerInstanceArn), "/")
+		cluster := "default"
+		if len(parts) == 3 {
+			cluster = parts[1]
+		}
+		if input.Cluster != nil && *input.Cluster != cluster && utils.ArnToName(input.Cluster) != cluster {
+			continue
+		}
+		arns = append(arns, *i.ContainerInstanceArn)
+	}
+	page, next, err := fixturePage(arns, input.MaxResults, input.NextToken, 100)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListContainerInstancesOutput{ContainerInstanceArns: page, NextToken: next}, nil
+}
+
+func (f *fixtureEcs) DescribeContainerInstances(_ context.Context, input *ecs.DescribeContainerInstancesInput, _ ...func(*ecs.Options)) (*ecs.DescribeContainerInstancesOutput, error) {
+	if err := checkBatchSize("ContainerInstances", len(input.ContainerInstances), 100); err != nil {
+		return nil, err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &ecs.DescribeContainerInstancesOutput{}
+	for _, ref := range input.ContainerInstances {
+		for _, i := range f.ContainerInstances {
+			if matchArn(&ref, i.ContainerInstanceArn) {
+				output.ContainerInstances = append(output.ContainerInstances, i)
+				break
+			}
+		}
+	}
+	return output, nil
+}
+
+type fixtureCloudwatch struct{ *fixtureBackend }
+
+func (f *fixtureCloudwatch) GetMetricData(_ context.Context, input *cloudwatch.GetMetricDataInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, q := range input.MetricDataQueries {
+		if q.MetricStat == nil || q.MetricStat.Metric == nil {
+			continue
+		}
+		metric := q.MetricStat.Metric
+		var cluster, resource, container string
+		for _, d := range metric.Dimensions {
+			switch aws.ToString(d.Name) {
+			case "ClusterName":
+				cluster = aws.ToString(d.Value)
+			case "ServiceName", "TaskId":
+				resource = aws.ToString(d.Value)
+			case "ContainerName":
+				container = "/" + aws.ToString(d.Value)
+			}
+		}
+		metrics := f.Metrics[cluster+"/"+resource+container]
+		var datapoints []cloudwatchTypes.Datapoint
+		switch aws.ToString(metric.MetricName) {
+		case CPU, TaskCPU, ContainerCPU:
+			datapoints = metrics.CPUUtilization
+		case Memory, TaskMemory, ContainerMemory:
+			datapoints = metrics.MemoryUtilization
+		case RunningTaskCount:
+			datapoints = metrics.RunningTaskCount
+		}
+		result := cloudwatchTypes.MetricDataResult{Id: q.Id, Label: metric.MetricName, StatusCode: cloudwatchTypes.StatusCodeComplete}
+		for _, d := range datapoints {
+			value := d.Average
+			if aws.ToString(q.MetricStat.Stat) == string(cloudwatchTypes.StatisticMaximum) {
+				value = d.Maximum
+			}
+			if d.Timestamp == nil || value == nil {
+				continue
+			}
+			result.Timestamps = append(result.Timestamps, *d.Timestamp)
+			result.Values = append(result.Values, *value)
+		}
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+	return output, nil
+}
+
+type fixtureCloudwatchlogs struct{ *fixtureBackend }
+
+func lastEventTimestamp(events []cloudwatchlogsTypes.OutputLogEvent) int64 {
+	if len(events) == 0 {
+		return 0
+	}
+	return aws.ToInt64(events[len(events)-1].Timestamp)
+}
+
+func (f *fixtureCloudwatchlogs) DescribeLogStreams(_ context.Context, input *cloudwatchlogs.DescribeLogStreamsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	streams, ok := f.Logs[aws.ToString(input.LogGroupName)]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: the specified log group %s does not exist", aws.ToString(input.LogGroupName))
+	}
+	output := &cloudwatchlogs.DescribeLogStreamsOutput{}
+	for name, events := range streams {
+		if input.LogStreamNamePrefix != nil && !strings.HasPrefix(name, *input.LogStreamNamePrefix) {
+			continue
+		}
+		output.LogStreams = append(output.LogStreams, cloudwatchlogsTypes.LogStream{
+			LogStreamName:      aws.String(name),
+			LastEventTimestamp: aws.Int64(lastEventTimestamp(events)),
+		})
+	}
+	sort.Slice(output.LogStreams, func(i, j int) bool {
+		a, b := output.LogStreams[i], output.LogStreams[j]
+		if input.OrderBy == cloudwatchlogsTypes.OrderByLastEventTime {
+			if aws.ToBool(input.Descending) {
+				return *a.LastEventTimestamp > *b.LastEventTimestamp
+			}
+			return *a.LastEventTimestamp < *b.LastEventTimestamp
+		}
+		return *a.LogStreamName < *b.LogStreamName
+	})
+	if input.Limit != nil && int(*input.Limit) < len(output.LogStreams) {
+		output.LogStreams = output.LogStreams[:*input.Limit]
+	}
+	return output, nil
+}
+
+// Tokens are "b/${index}" for events before index and "f/${index}" for events from index of the events in range
+func (f *fixtureCloudwatchlogs) GetLogEvents(_ context.Context, input *cloudwatchlogs.GetLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	stream, ok := f.Logs[aws.ToString(input.LogGroupName)][aws.ToString(input.LogStreamName)]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: the specified log stream %s does not exist", aws.ToString(input.LogStreamName))
+	}
+	events := []cloudwatchlogsTypes.OutputLogEvent{}
+	for _, e := range stream {
+		timestamp := aws.ToInt64(e.Timestamp)
+		if input.StartTime != nil && timestamp < *input.StartTime {
+			continue
+		}
+		// end time is exclusive
+		if input.EndTime != nil && timestamp >= *input.EndTime {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	limit := 10000
+	if input.Limit != nil {
+		limit = int(*input.Limit)
+	}
+	// Without a token GetLogEvents returns the latest events unless it starts from head
+	start, end := max(len(events)-limit, 0), len(events)
+	if aws.ToBool(input.StartFromHead) {
+		start, end = 0, min(limit, len(events))
+	}
+	if input.NextToken != nil {
+		direction, index, ok := strings.Cut(*input.NextToken, "/")
+		i, err := strconv.Atoi(index)
+		if !ok || err != nil || i < 0 || i > len(events) {
+			return nil, fmt.Errorf("InvalidParameterException: the specified nextToken is invalid")
+		}
+		if direction == "b" {
+			start, end = max(i-limit, 0), i
+		} else {
+			start, end = i, min(i+limit, len(events))
+		}
+	}
+	return &cloudwatchlogs.GetLogEventsOutput{
+		Events:            events[start:end],
+		NextBackwardToken: aws.String(fmt.Sprintf("b/%d", start)),
+		NextForwardToken:  aws.String(fmt.Sprintf("f/%d", end)),
+	}, nil
+}
+
+func (f *fixtureCloudwatchlogs) FilterLogEvents(_ context.Context, input *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	streams, ok := f.Logs[aws.ToString(input.LogGroupName)]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: the specified log group %s does not exist", aws.ToString(input.LogGroupName))
+	}
+	output := &cloudwatchlogs.FilterLogEventsOutput{}
+	for name, events := range streams {
+		if len(input.LogStreamNames) > 0 && !slices.Contains(input.LogStreamNames, name) {
+			continue
+		}
+		for i, e := range events {
+			timestamp := aws.ToInt64(e.Timestamp)
+			if input.StartTime != nil && timestamp < *input.StartTime {
+				continue
+			}
+			if input.EndTime != nil && timestamp > *input.EndTime {
+				continue
+			}
+			output.Events = append(output.Events, cloudwatchlogsTypes.FilteredLogEvent{
+				EventId:       aws.String(fmt.Sprintf("%s/%d", name, i)),
+				LogStreamName: aws.String(name),
+				Message:       e.Message,
+				Timestamp:     e.Timestamp,
+			})
+		}
+	}
+	sort.SliceStable(output.Events, func(i, j int) bool {
+		return *output.Events[i].Timestamp < *output.Events[j].Timestamp
+	})
+	return output, nil
+}
+
+var (
+	// Logs Insights commands understood by fixture queries, other commands are ignored
+	fixtureQueryFilter = regexp.MustCompile(`filter\s+(@\w+)\s+like\s+/((?:[^/\\]|\\.)*)/`)
+	fixtureQueryLimit  = regexp.MustCompile(`limit\s+(\d+)`)
+)
+
+// Fixture queries only support "filter @field like /regex/" and "limit n", results are sorted by latest first
+func (f *fixtureCloudwatchlogs) StartQuery(_ context.Context, input *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := aws.ToString(input.QueryString)
+	type filter struct {
+		field   string
+		pattern *regexp.Regexp
+	}
+	filters := []filter{}
+	for _, m := range fixtureQueryFilter.FindAllStringSubmatch(query, -1) {
+		pattern, err := regexp.Compile(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("MalformedQueryException: %v", err)
+		}
+		filters = append(filters, filter{field: m[1], pattern: pattern})
+	}
+	limit := 10000
+	if m := fixtureQueryLimit.FindStringSubmatch(query); m != nil {
+		limit, _ = strconv.Atoi(m[1])
+	}
+
+	type record struct {
+		timestamp int64
+		fields    map[string]string
+	}
+	records := []record{}
+	for _, group := range append(slices.Clone(input.LogGroupNames), input.LogGroupIdentifiers...) {
+		streams, ok := f.Logs[group]
+		if !ok {
+			return nil, fmt.Errorf("ResourceNotFoundException: the specified log group %s does not exist", group)
+		}
+		for name, events := range streams {
+			for _, e := range events {
+				timestamp := aws.ToInt64(e.Timestamp)
+				if timestamp < aws.ToInt64(input.StartTime)*1000 || timestamp >= (aws.ToInt64(input.EndTime)+1)*1000 {
+					continue
+				}
+				fields := map[string]string{
+					"@timestamp": time.UnixMilli(timestamp).UTC().Format("2006-01-02 15:04:05.000"),
+					"@log":       group,
+					"@logStream": name,
+					"@message":   aws.ToString(e.Message),
+				}
+				if slices.ContainsFunc(filters, func(q filter) bool { return !q.pattern.MatchString(fields[q.field]) }) {
+					continue
+				}
+				records = append(records, record{timestamp: timestamp, fields: fields})
+			}
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].timestamp > records[j].timestamp
+	})
+	// like CloudWatch, matched records are counted before the limit
+	matched := len(records)
+	records = records[:min(len(records), limit)]
+
+	output := &cloudwatchlogs.GetQueryResultsOutput{
+		Status:     cloudwatchlogsTypes.QueryStatusComplete,
+		Statistics: &cloudwatchlogsTypes.QueryStatistics{RecordsMatched: float64(matched)},
+	}
+	for i, r := range records {
+		row := []cloudwatchlogsTypes.ResultField{}
+		for _, field := range []string{"@timestamp", "@logStream", "@message"} {
+			row = append(row, cloudwatchlogsTypes.ResultField{Field: aws.String(field), Value: aws.String(r.fields[field])})
+		}
+		row = append(row, cloudwatchlogsTypes.ResultField{Field: aws.String("@ptr"), Value: aws.String(strconv.Itoa(i))})
+		output.Results = append(output.Results, row)
+	}
+	for _, streams := range f.Logs {
+		for _, events := range streams {
+			output.Statistics.RecordsScanned += float64(len(events))
+		}
+	}
+
+	if f.queries == nil {
+		f.queries = map[string]*cloudwatchlogs.GetQueryResultsOutput{}
+	}
+	queryId := fmt.Sprintf("fixture-query-%d", len(f.queries)+1)
+	f.queries[queryId] = output
+	return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String(queryId)}, nil
+}
+
+func (f *fixtureCloudwatchlogs) GetQueryResults(_ context.Context, input *cloudwatchlogs.GetQueryResultsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	output, ok := f.queries[aws.ToString(input.QueryId)]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: the specified query %s does not exist", aws.ToString(input.QueryId))
+	}
+	return output, nil
+}
+
+// Fixture queries complete when started, there is nothing to stop
+func (f *fixtureCloudwatchlogs) StopQuery(_ context.Context, _ *cloudwatchlogs.StopQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StopQueryOutput, error) {
+	return &cloudwatchlogs.StopQueryOutput{Success: false}, nil
+}
+
+type fixtureAutoScaling struct{ *fixtureBackend }
+
+func (f *fixtureAutoScaling) get(resourceId *string) AutoScalingData {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.AutoScaling[aws.ToString(resourceId)]
+}
+
+func (f *fixtureAutoScaling) DescribeScalableTargets(_ context.Context, input *applicationautoscaling.DescribeScalableTargetsInput, _ ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalableTargetsOutput, error) {
+	output := &applicationautoscaling.DescribeScalableTargetsOutput{}
+	for _, id := range input.ResourceIds {
+		output.ScalableTargets = append(output.ScalableTargets, f.get(&id).Targets...)
+	}
+	return output, nil
+}
+
+func (f *fixtureAutoScaling) DescribeScalingPolicies(_ context.Context, input *applicationautoscaling.DescribeScalingPoliciesInput, _ ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalingPoliciesOutput, error) {
+	return &applicationautoscaling.DescribeScalingPoliciesOutput{ScalingPolicies: f.get(input.ResourceId).Policies}, nil
+}
+
+func (f *fixtureAutoScaling) DescribeScalingActivities(_ context.Context, input *applicationautoscaling.DescribeScalingActivitiesInput, _ ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalingActivitiesOutput, error) {
+	return &applicationautoscaling.DescribeScalingActivitiesOutput{ScalingActivities: f.get(input.ResourceId).Activities}, nil
+}
+
+func (f *fixtureAutoScaling) DescribeScheduledActions(_ context.Context, input *applicationautoscaling.DescribeScheduledActionsInput, _ ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScheduledActionsOutput, error) {
+	return &applicationautoscaling.DescribeScheduledActionsOutput{ScheduledActions: f.get(input.ResourceId).Actions}, nil
+}
+
+// Sessions need a real session-manager-plugin target, fixtures can not start them
+type fixtureSsm struct{ *fixtureBackend }
+
+func (f *fixtureSsm) StartSession(context.Context, *ssm.StartSessionInput, ...func(*ssm.Options)) (*ssm.StartSessionOutput, error) {
+	return nil, fmt.Errorf("start session %w", errFixtureUnsupported)
+}
+
+func (f *fixtureSsm) TerminateSession(_ context.Context, input *ssm.TerminateSessionInput, _ ...func(*ssm.Options)) (*ssm.TerminateSessionOutput, error) {
+	return &ssm.TerminateSessionOutput{SessionId: input.SessionId}, nil
+}
+
+type fixtureAccount struct{ *fixtureBackend }
+
+func (f *fixtureAccount) ListRegions(context.Context, *account.ListRegionsInput, ...func(*account.Options)) (*account.ListRegionsOutput, error) {
+	output := &account.ListRegionsOutput{}
+	for _, r := range f.Regions {
+		output.Regions = append(output.Regions, accountTypes.Region{
+			RegionName:      aws.String(r),
+			RegionOptStatus: accountTypes.RegionOptStatusEnabledByDefault,
+		})
+	}
+	return output, nil
+}
+
+type fixtureIam struct{ *fixtureBackend }
+
+func (f *fixtureIam) ListAccountAliases(context.Context, *iam.ListAccountAliasesInput, ...func(*iam.Options)) (*iam.ListAccountAliasesOutput, error) {
+	return &iam.ListAccountAliasesOutput{AccountAliases: []string{"demo"}}, nil
+}
+
+type fixtureSts struct{ *fixtureBackend }
+
+// Snapshot account is the account of its first cluster
+func (f *fixtureSts) GetCallerIdentity(context.Context, *sts.GetCallerIdentityInput, ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	accountID := "123456789012"
+	if len(f.Clusters) > 0 {
+		if id := utils.ArnToAccount(f.Clusters[0].ClusterArn); id != "" {
+			accountID = id
+		}
+	}
+	return &sts.GetCallerIdentityOutput{
+		Account: aws.String(accountID),
+		Arn:     aws.String(fmt.Sprintf("arn:aws:sts::%s:assumed-role/demo/e1s", accountID)),
+		UserId:  aws.String("AROADEMO:e1s"),
+	}, nil
+}
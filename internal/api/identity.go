@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Identity the store acts as
+type Identity struct {
+	Account string
+	// Account alias, empty when the account has none or without iam:ListAccountAliases permission
+	AccountAlias string
+	Arn          string
+	// Assumed role or IAM user name
+	Principal string
+	// Role session name, empty when not an assumed role
+	Session string
+	// Credentials expiry, zero when credentials don't expire
+	Expires time.Time
+}
+
+// Equivalent to
+// aws sts get-caller-identity
+// aws iam list-account-aliases
+func (store *Store) GetIdentity(ctx context.Context) (Identity, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+	identity, err := cached(ctx, store, cacheIdentity, "", store.getIdentity)
+	if err != nil {
+		return identity, err
+	}
+
+	// Credentials are cached by the SDK, expiry is read on every call for the countdown
+	if store.Credentials != nil {
+		creds, err := store.Credentials.Retrieve(ctx)
+		if err == nil && creds.CanExpire {
+			identity.Expires = creds.Expires
+		}
+	}
+	return identity, nil
+}
+
+func (store *Store) getIdentity(ctx context.Context) (Identity, error) {
+	store.initStsClient()
+	output, err := store.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		slog.Warn("failed to run aws api get caller identity", "error", err)
+		return Identity{}, err
+	}
+	identity := Identity{
+		Account: aws.ToString(output.Account),
+		Arn:     aws.ToString(output.Arn),
+	}
+	identity.Principal, identity.Session = parseCallerArn(identity.Arn)
+
+	store.initIamClient()
+	aliases, err := store.iam.ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+	if err != nil {
+		// The context line still shows the account ID
+		slog.Debug("failed to run aws api list account aliases", "error", err)
+	} else if len(aliases.AccountAliases) > 0 {
+		// An account has at most one alias
+		identity.AccountAlias = aliases.AccountAliases[0]
+	}
+	return identity, nil
+}
+
+// Principal and session name of a caller ARN, e.g.
+// arn:aws:sts::123456789012:assumed-role/Admin/alice -> Admin, alice
+// arn:aws:iam::123456789012:user/bob -> bob
+func parseCallerArn(arn string) (principal string, session string) {
+	ss := strings.SplitN(arn, ":", 6)
+	if len(ss) < 6 {
+		return arn, ""
+	}
+	parts := strings.Split(ss[5], "/")
+	switch {
+	case parts[0] == "assumed-role" && len(parts) >= 3:
+		return parts[1], parts[len(parts)-1]
+	case len(parts) >= 2:
+		return parts[len(parts)-1], ""
+	}
+	return ss[5], ""
+}
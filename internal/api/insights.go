@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const (
+	// Interval of GetQueryResults polling while a query runs
+	queryPollInterval = time.Second
+	// Field of result rows used to fetch the log record, not shown
+	queryPointerField = "@ptr"
+)
+
+// Logs Insights query over log groups in a time range
+type LogsQuery struct {
+	LogGroups []string
+	Query     string
+	Start     time.Time
+	End       time.Time
+}
+
+// Logs Insights query results, fields in order of first appearance
+type LogsQueryResult struct {
+	Fields         []string
+	Rows           [][]string
+	RecordsMatched float64
+	RecordsScanned float64
+}
+
+// Equivalent to
+//
+//	query_id=$(aws logs start-query \
+//	  --log-group-names "$log_group" \
+//	  --start-time "$(date -u -v -1H +%s)" \
+//	  --end-time "$(date -u +%s)" \
+//	  --query-string 'fields @timestamp, @logStream, @message | sort @timestamp desc | limit 100' \
+//	  --query queryId \
+//	  --output text)
+//
+//	aws logs get-query-results --query-id "$query_id"
+//
+// Run query and wait for its results, the query is stopped when ctx is done before it completes
+func (store *Store) QueryLogs(ctx context.Context, q LogsQuery) (*LogsQueryResult, error) {
+	store.initCloudwatchlogsClient()
+
+	startCtx, cancel := store.withTimeout(ctx)
+	defer cancel()
+	started, err := store.cloudwatchlogs.StartQuery(startCtx, &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: q.LogGroups,
+		QueryString:   aws.String(q.Query),
+		StartTime:     aws.Int64(q.Start.Unix()),
+		EndTime:       aws.Int64(q.End.Unix()),
+	})
+	if err != nil {
+		slog.Warn("failed to run aws api to start query", "logGroups", q.LogGroups, "error", err)
+		return nil, err
+	}
+	queryId := started.QueryId
+
+	for {
+		select {
+		case <-time.After(queryPollInterval):
+		case <-ctx.Done():
+			store.stopQuery(queryId)
+			return nil, ctx.Err()
+		}
+
+		pollCtx, cancel := store.withTimeout(ctx)
+		output, err := store.cloudwatchlogs.GetQueryResults(pollCtx, &cloudwatchlogs.GetQueryResultsInput{
+			QueryId: queryId,
+		})
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				store.stopQuery(queryId)
+			}
+			slog.Warn("failed to run aws api to get query results", "queryId", aws.ToString(queryId), "error", err)
+			return nil, err
+		}
+
+		switch output.Status {
+		case cloudwatchlogsTypes.QueryStatusScheduled, cloudwatchlogsTypes.QueryStatusRunning:
+			continue
+		case cloudwatchlogsTypes.QueryStatusComplete:
+			return queryResult(output), nil
+		default:
+			err := fmt.Errorf("query %s %s", aws.ToString(queryId), output.Status)
+			slog.Warn("failed to run logs insights query", "error", err)
+			return nil, err
+		}
+	}
+}
+
+// Equivalent to
+//
+//	aws logs stop-query --query-id "$query_id"
+func (store *Store) stopQuery(queryId *string) {
+	ctx, cancel := store.withTimeout(context.Background())
+	defer cancel()
+	_, err := store.cloudwatchlogs.StopQuery(ctx, &cloudwatchlogs.StopQueryInput{QueryId: queryId})
+	if err != nil {
+		slog.Warn("failed to run aws api to stop query", "queryId", aws.ToString(queryId), "error", err)
+	}
+}
+
+func queryResult(output *cloudwatchlogs.GetQueryResultsOutput) *LogsQueryResult {
+	result := &LogsQueryResult{}
+	if output.Statistics != nil {
+		result.RecordsMatched = output.Statistics.RecordsMatched
+		result.RecordsScanned = output.Statistics.RecordsScanned
+	}
+
+	columns := map[string]int{}
+	for _, row := range output.Results {
+		for _, f := range row {
+			field := aws.ToString(f.Field)
+			if _, ok := columns[field]; ok || field == queryPointerField {
+				continue
+			}
+			columns[field] = len(result.Fields)
+			result.Fields = append(result.Fields, field)
+		}
+	}
+	for _, row := range output.Results {
+		values := make([]string, len(result.Fields))
+		for _, f := range row {
+			if i, ok := columns[aws.ToString(f.Field)]; ok {
+				values[i] = aws.ToString(f.Value)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 )
@@ -12,7 +13,16 @@ import (
 // Equivalent to:
 // aws ecs list-container-instances --cluster ${cluster}
 // aws ecs describe-container-instances --cluster ${cluster} --container-instances ${instance1} ${instance2}
-func (store *Store) ListContainerInstances(cluster *string) ([]types.ContainerInstance, error) {
+func (store *Store) ListContainerInstances(ctx context.Context, cluster *string) ([]types.ContainerInstance, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	return cached(ctx, store, cacheInstances, aws.ToString(cluster), func(ctx context.Context) ([]types.ContainerInstance, error) {
+		return store.listContainerInstances(ctx, cluster)
+	})
+}
+
+func (store *Store) listContainerInstances(ctx context.Context, cluster *string) ([]types.ContainerInstance, error) {
 	batchSize := 100
 	limit := int32(batchSize)
 	params := &ecs.ListContainerInstancesInput{
@@ -20,7 +30,7 @@ func (store *Store) ListContainerInstances(cluster *string) ([]types.ContainerIn
 		MaxResults: &limit,
 	}
 
-	listOutput, err := store.ecs.ListContainerInstances(context.Background(), params)
+	listOutput, err := store.ecs.ListContainerInstances(ctx, params)
 	if err != nil {
 		slog.Warn("failed to run aws api to list container instances", "error", err)
 		return []types.ContainerInstance{}, err
@@ -32,15 +42,20 @@ func (store *Store) ListContainerInstances(cluster *string) ([]types.ContainerIn
 	}
 
 	// Get detailed information about the container instances
-	describeOutput, err := store.ecs.DescribeContainerInstances(context.Background(), &ecs.DescribeContainerInstancesInput{
-		Cluster:            cluster,
-		ContainerInstances: listOutput.ContainerInstanceArns,
+	results, err := fetchBatches(ctx, listOutput.ContainerInstanceArns, batchSize, func(ctx context.Context, instances []string) ([]types.ContainerInstance, error) {
+		describeOutput, err := store.ecs.DescribeContainerInstances(ctx, &ecs.DescribeContainerInstancesInput{
+			Cluster:            cluster,
+			ContainerInstances: instances,
+		})
+		if err != nil {
+			slog.Warn("failed to run aws api to describe container instances", "error", err)
+			return nil, err
+		}
+		return describeOutput.ContainerInstances, nil
 	})
-
 	if err != nil {
-		slog.Warn("failed to run aws api to describe container instances", "error", err)
 		return []types.ContainerInstance{}, err
 	}
 
-	return describeOutput.ContainerInstances, nil
+	return results, nil
 }
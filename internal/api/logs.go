@@ -2,160 +2,195 @@ package api
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
-	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 )
 
 const (
-	logFmt = "[aqua::]%s[-:-:-]:%s\n"
+	// Log events in a page of a service log stream
+	ServiceLogLimit = 100
+	// Log events in a page of a task container log stream
+	ContainerLogLimit = 50
+	// Log streams of each log group to choose from
+	logStreamsLimit = 50
+	// GetLogEvents calls paging back over empty pages before an empty page is returned
+	maxEmptyLogPages = 5
+	// Task ID characters naming the task of a service log stream
+	shortTaskIdLength = 8
 )
 
-// Equivalent to
-// latest_log_stream=$(aws logs describe-log-streams \
-//   --log-group-name "$log_group" \
-//   --limit 1 \
-//   --order-by "LastEventTime" \
-//   --descending \
-//   --query "logStreams[0].logStreamName" \
-//   --output text)
+// Log stream of a log group
+type LogStream struct {
+	Group string
+	Name  string
+	// Container writing to the log stream, empty when unknown
+	Container string
+	// Zero when unknown
+	LastEventTime time.Time
+}
+
+// Time range of log events, zero times are unbounded
+type LogRange struct {
+	Since time.Time
+	Until time.Time
+}
 
-// # Get the latest 100 log events from the log stream
+// Log events of a log stream in ascending time order
+type LogStreamPage struct {
+	LogStream
+	Events []cloudwatchlogsTypes.OutputLogEvent
+	// Token of older events, nil once the oldest event in range is loaded
+	BackwardToken *string
+}
+
+// Equivalent to
 //
-//	aws logs get-log-events \
+//	aws logs describe-log-streams \
 //	  --log-group-name "$log_group" \
-//	  --log-stream-name "$latest_log_stream" \
-//	  --limit 100 \
-//	  --query "events[*].[timestamp,message]" \
-//	  --output table
-func (store *Store) GetServiceLogs(tdArn *string) ([]string, error) {
-	store.initCloudwatchlogsClient()
-	td, err := store.DescribeTaskDefinition(tdArn)
-
+//	  --order-by "LastEventTime" \
+//	  --descending \
+//	  --limit 50
+//
+// Get log streams of awslogs log groups of task definition containers, latest first in each log group
+func (store *Store) GetServiceLogStreams(ctx context.Context, tdArn *string) ([]LogStream, error) {
+	targets, err := store.GetLogTargets(ctx, tdArn, "", "")
 	if err != nil {
-		slog.Warn("failed to run aws api to describe task definition", "error", err)
 		return nil, err
 	}
 
-	logs := []string{}
-	logGroupNames := make(map[string]bool)
-	for _, c := range td.ContainerDefinitions {
-		if c.LogConfiguration == nil {
-			continue
-		}
-		if c.LogConfiguration.LogDriver != types.LogDriverAwslogs {
-			continue
-		}
-		groupName := c.LogConfiguration.Options["awslogs-group"]
-		if groupName == "" {
-			continue
-		}
-
-		// avoid the same log group for different containers
-		if _, ok := logGroupNames[groupName]; ok {
-			continue
-		}
-		logGroupNames[groupName] = true
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+	store.initCloudwatchlogsClient()
 
-		describeLogStreamsInput := &cloudwatchlogs.DescribeLogStreamsInput{
-			LogGroupName: &groupName,
-			Limit:        aws.Int32(1),
+	streams := []LogStream{}
+	for _, target := range targets {
+		output, err := store.cloudwatchlogs.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName: aws.String(target.Group),
+			Limit:        aws.Int32(logStreamsLimit),
 			OrderBy:      cloudwatchlogsTypes.OrderByLastEventTime,
 			Descending:   aws.Bool(true),
-		}
-		describeLogStreamsOutput, err := store.cloudwatchlogs.DescribeLogStreams(context.Background(), describeLogStreamsInput)
+		})
 		if err != nil {
-			slog.Warn("failed to run aws api to describe log stream", "error", err)
+			slog.Warn("failed to run aws api to describe log stream", "logGroup", target.Group, "error", err)
 			continue
 		}
-		streamName := describeLogStreamsOutput.LogStreams[0].LogStreamName
-
-		getLogEventsInput := &cloudwatchlogs.GetLogEventsInput{
-			LogGroupName:  &groupName,
-			LogStreamName: streamName,
-			Limit:         aws.Int32(100),
+		for _, s := range output.LogStreams {
+			stream := LogStream{Group: target.Group, Name: aws.ToString(s.LogStreamName)}
+			if s.LastEventTimestamp != nil {
+				stream.LastEventTime = time.UnixMilli(*s.LastEventTimestamp)
+			}
+			streams = append(streams, stream)
 		}
-		getLogEventsOutput, err := store.cloudwatchlogs.GetLogEvents(context.Background(), getLogEventsInput)
-		if err != nil {
-			slog.Warn("failed to run aws api to get log events", "error", err)
-			continue
-		}
-		if len(getLogEventsOutput.Events) == 0 {
-			continue
+	}
+	return streams, nil
+}
+
+// Newest log stream of each task in each log group, streams are latest first in each log group.
+// Container of the streams names the container and the task, the newest stream of a log group
+// is kept as it is when none of its streams belongs to the tasks.
+func TaskLogStreams(streams []LogStream, taskIds []string) []LogStream {
+	result := []LogStream{}
+	for start := 0; start < len(streams); {
+		end := start + 1
+		for end < len(streams) && streams[end].Group == streams[start].Group {
+			end++
+		}
+		group := streams[start:end]
+		start = end
+
+		found := false
+		for _, taskId := range taskIds {
+			// "${prefix}/${containerName}/${taskId}"
+			i := slices.IndexFunc(group, func(s LogStream) bool { return strings.HasSuffix(s.Name, "/"+taskId) })
+			if i < 0 {
+				continue
+			}
+			stream := group[i]
+			stream.Container = path.Base(strings.TrimSuffix(stream.Name, "/"+taskId)) + "/" + taskId[:min(len(taskId), shortTaskIdLength)]
+			result = append(result, stream)
+			found = true
+		}
+		if !found {
+			result = append(result, group[0])
 		}
-		logs = append(logs, fmt.Sprintf("Log stream name: %s\n", *streamName))
-		for _, e := range getLogEventsOutput.Events {
-			logs = append(logs, fmt.Sprintf(logFmt, time.Unix(0, *e.Timestamp*int64(time.Millisecond)).Format(time.RFC3339), *e.Message))
+	}
+	return result
+}
+
+// Get log streams of task containers, all containers when containerName is empty
+func (store *Store) GetTaskLogStreams(ctx context.Context, tdArn *string, taskId string, containerName string) ([]LogStream, error) {
+	targets, err := store.GetLogTargets(ctx, tdArn, taskId, containerName)
+	if err != nil {
+		return nil, err
+	}
+	streams := []LogStream{}
+	for _, target := range targets {
+		for _, name := range target.Streams {
+			// "${prefix}/${containerName}/${taskId}"
+			container := path.Base(strings.TrimSuffix(name, "/"+taskId))
+			streams = append(streams, LogStream{Group: target.Group, Name: name, Container: container})
 		}
 	}
-	return logs, nil
+	return streams, nil
 }
 
-// Get the 50 log events for each container
 // Equivalent to
 //
 //	aws logs get-log-events \
 //	  --log-group-name "$log_group" \
-//	  --log-stream-name "$stream_prefix/$container_name/$task_id" \
-//	  --limit 50 \
-//	  --query "events[*].[timestamp,message]" \
-//	  --output table
-func (store *Store) GetLogStreamLogs(tdArn *string, taskId string, containerName string) ([]string, error) {
+//	  --log-stream-name "$log_stream" \
+//	  --start-time "$since" \
+//	  --end-time "$until" \
+//	  --next-token "$backward_token" \
+//	  --no-start-from-head \
+//	  --limit 100
+//
+// Get latest log events of stream in range, or the events before token when token is not nil.
+// Empty pages are skipped up to maxEmptyLogPages, only the start of the stream clears BackwardToken.
+func (store *Store) GetLogEventsPage(ctx context.Context, stream LogStream, r LogRange, token *string, limit int32) (*LogStreamPage, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
 	store.initCloudwatchlogsClient()
-	td, err := store.DescribeTaskDefinition(tdArn)
 
-	if err != nil {
-		slog.Warn("failed to run aws api to describe task definition", "error", err)
-		return nil, err
+	input := &cloudwatchlogs.GetLogEventsInput{
+		LogGroupName:  aws.String(stream.Group),
+		LogStreamName: aws.String(stream.Name),
+		StartFromHead: aws.Bool(false),
+		NextToken:     token,
+		Limit:         aws.Int32(limit),
 	}
-
-	logs := []string{}
-	for _, c := range td.ContainerDefinitions {
-		if *c.Name != containerName && containerName != "" {
-			continue
-		}
-		if c.LogConfiguration == nil {
-			continue
-		}
-		if c.LogConfiguration.LogDriver != types.LogDriverAwslogs {
-			continue
-		}
-		groupName := c.LogConfiguration.Options["awslogs-group"]
-		if groupName == "" {
-			continue
-		}
-
-		streamPrefix := *c.Name
-
-		if _, ok := c.LogConfiguration.Options["awslogs-stream-prefix"]; ok {
-			streamPrefix = c.LogConfiguration.Options["awslogs-stream-prefix"]
-		}
-
-		streamName := fmt.Sprintf("%s/%s/%s", streamPrefix, *c.Name, taskId)
-
-		getLogEventsInput := &cloudwatchlogs.GetLogEventsInput{
-			LogGroupName:  &groupName,
-			LogStreamName: &streamName,
-			Limit:         aws.Int32(50),
-		}
-		getLogEventsOutput, err := store.cloudwatchlogs.GetLogEvents(context.Background(), getLogEventsInput)
+	if !r.Since.IsZero() {
+		input.StartTime = aws.Int64(r.Since.UnixMilli())
+	}
+	if !r.Until.IsZero() {
+		input.EndTime = aws.Int64(r.Until.UnixMilli())
+	}
+	page := &LogStreamPage{LogStream: stream}
+	for range maxEmptyLogPages {
+		output, err := store.cloudwatchlogs.GetLogEvents(ctx, input)
 		if err != nil {
-			slog.Warn("failed to run aws api to get log events", "error", err)
-			continue
+			slog.Warn("failed to run aws api to get log events", "logGroup", stream.Group, "logStream", stream.Name, "error", err)
+			return nil, err
 		}
-
-		if len(getLogEventsOutput.Events) == 0 {
-			continue
+		page.Events = output.Events
+		// the same token is returned at the start of the stream
+		if output.NextBackwardToken == nil || (input.NextToken != nil && *output.NextBackwardToken == *input.NextToken) {
+			page.BackwardToken = nil
+			return page, nil
 		}
-		logs = append(logs, fmt.Sprintf("Log stream name: %s\n", streamName))
-		for _, e := range getLogEventsOutput.Events {
-			logs = append(logs, fmt.Sprintf(logFmt, time.Unix(0, *e.Timestamp*int64(time.Millisecond)).Format(time.RFC3339), *e.Message))
+		page.BackwardToken = output.NextBackwardToken
+		if len(output.Events) > 0 {
+			break
 		}
+		// pages in the middle of a stream may be empty, e.g. across a gap in time
+		input.NextToken = output.NextBackwardToken
 	}
-	return logs, nil
+	return page, nil
 }
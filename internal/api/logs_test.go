@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// Log stream pages by token, the empty token is the latest page
+type fakeLogPages struct {
+	cloudwatchlogsAPI
+	pages map[string]*cloudwatchlogs.GetLogEventsOutput
+	calls int
+}
+
+func (f *fakeLogPages) GetLogEvents(ctx context.Context, input *cloudwatchlogs.GetLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	f.calls++
+	return f.pages[aws.ToString(input.NextToken)], nil
+}
+
+func logEventsPage(backwardToken string, timestamps ...int64) *cloudwatchlogs.GetLogEventsOutput {
+	output := &cloudwatchlogs.GetLogEventsOutput{NextBackwardToken: aws.String(backwardToken)}
+	for _, ts := range timestamps {
+		output.Events = append(output.Events, cloudwatchlogsTypes.OutputLogEvent{Timestamp: aws.Int64(ts)})
+	}
+	return output
+}
+
+func TestLogEventsPageAcrossGaps(t *testing.T) {
+	fake := &fakeLogPages{pages: map[string]*cloudwatchlogs.GetLogEventsOutput{
+		"":   logEventsPage("b3", 4000),
+		"b3": logEventsPage("b2"),
+		"b2": logEventsPage("b1"),
+		"b1": logEventsPage("b0", 1000),
+		"b0": logEventsPage("b0"),
+	}}
+	store := &Store{cloudwatchlogs: fake}
+	ctx := context.Background()
+
+	page, err := store.GetLogEventsPage(ctx, LogStream{}, LogRange{}, nil, 10)
+	if err != nil || len(page.Events) != 1 || aws.ToString(page.BackwardToken) != "b3" {
+		t.Fatalf("Got page %+v, err %v, Want: latest event and token b3", page, err)
+	}
+	// empty pages of a gap in time are skipped
+	page, err = store.GetLogEventsPage(ctx, LogStream{}, LogRange{}, page.BackwardToken, 10)
+	if err != nil || len(page.Events) != 1 || aws.ToInt64(page.Events[0].Timestamp) != 1000 || aws.ToString(page.BackwardToken) != "b0" {
+		t.Fatalf("Got page %+v, err %v, Want: event before the gap and token b0", page, err)
+	}
+	page, err = store.GetLogEventsPage(ctx, LogStream{}, LogRange{}, page.BackwardToken, 10)
+	if err != nil || len(page.Events) != 0 || page.BackwardToken != nil {
+		t.Fatalf("Got page %+v, err %v, Want: start of the stream", page, err)
+	}
+
+	// a long gap keeps the token to page on later
+	fake.pages = map[string]*cloudwatchlogs.GetLogEventsOutput{}
+	for i := range 10 {
+		fake.pages[fmt.Sprintf("gap%d", i)] = logEventsPage(fmt.Sprintf("gap%d", i+1))
+	}
+	fake.calls = 0
+	page, err = store.GetLogEventsPage(ctx, LogStream{}, LogRange{}, aws.String("gap0"), 10)
+	if err != nil || page.BackwardToken == nil || fake.calls != maxEmptyLogPages {
+		t.Errorf("Got page %+v after %d calls, err %v, Want: token kept after %d calls", page, fake.calls, err, maxEmptyLogPages)
+	}
+}
+
+func TestTaskLogStreams(t *testing.T) {
+	streams := []LogStream{
+		{Group: "/ecs/web", Name: "ecs/web/task2"},
+		{Group: "/ecs/web", Name: "ecs/envoy/task1"},
+		{Group: "/ecs/web", Name: "ecs/web/task1"},
+		{Group: "/ecs/web", Name: "ecs/web/task0"},
+		{Group: "/ecs/other", Name: "other/other/old"},
+	}
+	got := TaskLogStreams(streams, []string{"task1", "task2"})
+	want := []LogStream{
+		{Group: "/ecs/web", Name: "ecs/envoy/task1", Container: "envoy/task1"},
+		{Group: "/ecs/web", Name: "ecs/web/task2", Container: "web/task2"},
+		{Group: "/ecs/other", Name: "other/other/old"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Got %+v, Want: %+v", got, want)
+	}
+}
@@ -2,120 +2,306 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/utils"
 )
 
 const (
-	Namespace = "AWS/ECS"
-	CPU       = "CPUUtilization"
-	Memory    = "MemoryUtilization"
+	Namespace                  = "AWS/ECS"
+	ContainerInsightsNamespace = "ECS/ContainerInsights"
+	CPU                        = "CPUUtilization"
+	Memory                     = "MemoryUtilization"
+	RunningTaskCount           = "RunningTaskCount"
+	TaskCPU                    = "TaskCpuUtilization"
+	TaskMemory                 = "TaskMemoryUtilization"
+	ContainerCPU               = "ContainerCpuUtilization"
+	ContainerMemory            = "ContainerMemoryUtilization"
+
+	// GetMetricData limit of queries in one request
+	maxMetricDataQueries = 500
 )
 
+// Metrics of a fixture snapshot
 type MetricsData struct {
 	CPUUtilization    []types.Datapoint
 	MemoryUtilization []types.Datapoint
+	// Only available with Container Insights
+	RunningTaskCount []types.Datapoint
 }
 
-// Get ECS service metrics(CPU, Memory)
-func (store *Store) GetMetrics(cluster, service *string) (*MetricsData, error) {
-	cpu, err := store.getCPU(cluster, service)
-	if err != nil {
-		return nil, err
+// Time range of metrics and the period of its datapoints
+type MetricsWindow struct {
+	Name     string
+	Duration time.Duration
+	// Seconds
+	Period int32
+}
+
+// Selectable windows of the metrics page
+var MetricsWindows = []MetricsWindow{
+	{Name: "1h", Duration: time.Hour, Period: 60},
+	{Name: "6h", Duration: 6 * time.Hour, Period: 300},
+	{Name: "24h", Duration: 24 * time.Hour, Period: 900},
+	{Name: "7d", Duration: 7 * 24 * time.Hour, Period: 3600},
+}
+
+// Datapoints of one metric statistic in ascending time order
+type MetricSeries struct {
+	Timestamps []time.Time
+	Values     []float64
+}
+
+// Latest value of series, false if series has no datapoints
+func (s MetricSeries) Last() (float64, bool) {
+	if len(s.Values) == 0 {
+		return 0, false
+	}
+	return s.Values[len(s.Values)-1], true
+}
+
+// Service metrics over a window
+type ServiceMetrics struct {
+	Window        MetricsWindow
+	CPUAverage    MetricSeries
+	CPUMaximum    MetricSeries
+	MemoryAverage MetricSeries
+	MemoryMaximum MetricSeries
+	// Empty without Container Insights
+	RunningTaskCount MetricSeries
+}
+
+// Equivalent to
+//
+//	aws cloudwatch get-metric-data \
+//		--start-time "$(date -u -v -1H +'%Y-%m-%dT%H:%M:%SZ')" \
+//		--end-time "$(date -u +'%Y-%m-%dT%H:%M:%SZ')" \
+//		--scan-by TimestampAscending \
+//		--metric-data-queries '[{"Id":"cpu_avg","MetricStat":{"Metric":{"Namespace":"AWS/ECS","MetricName":"CPUUtilization","Dimensions":[{"Name":"ClusterName","Value":"${clusterName}"},{"Name":"ServiceName","Value":"${serviceName}"}]},"Period":60,"Stat":"Average"}}, ...]'
+//
+// Get CPU and memory utilization(average, maximum) and running task count of a service over window
+func (store *Store) GetServiceMetrics(ctx context.Context, cluster, service *string, window MetricsWindow) (*ServiceMetrics, error) {
+	store.initCloudwatchClient()
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	dimensions := []types.Dimension{
+		{
+			Name:  aws.String("ClusterName"),
+			Value: cluster,
+		},
+		{
+			Name:  aws.String("ServiceName"),
+			Value: service,
+		},
+	}
+	query := func(id, namespace, name string, stat types.Statistic) types.MetricDataQuery {
+		return types.MetricDataQuery{
+			Id: aws.String(id),
+			MetricStat: &types.MetricStat{
+				Metric: &types.Metric{
+					Namespace:  aws.String(namespace),
+					MetricName: aws.String(name),
+					Dimensions: dimensions,
+				},
+				Period: aws.Int32(window.Period),
+				Stat:   aws.String(string(stat)),
+			},
+		}
+	}
+	queries := []types.MetricDataQuery{
+		query("cpu_avg", Namespace, CPU, types.StatisticAverage),
+		query("cpu_max", Namespace, CPU, types.StatisticMaximum),
+		query("mem_avg", Namespace, Memory, types.StatisticAverage),
+		query("mem_max", Namespace, Memory, types.StatisticMaximum),
+		query("tasks", ContainerInsightsNamespace, RunningTaskCount, types.StatisticAverage),
 	}
 
-	memory, err := store.getMemory(cluster, service)
+	series, err := store.getMetricData(ctx, queries, window.Duration)
 	if err != nil {
+		slog.Warn("failed to run aws api", "metrics", "GetMetricData", "cluster", *cluster, "service", *service, "error", err)
 		return nil, err
 	}
 
-	return &MetricsData{
-		CPUUtilization:    cpu,
-		MemoryUtilization: memory,
+	return &ServiceMetrics{
+		Window:           window,
+		CPUAverage:       series["cpu_avg"],
+		CPUMaximum:       series["cpu_max"],
+		MemoryAverage:    series["mem_avg"],
+		MemoryMaximum:    series["mem_max"],
+		RunningTaskCount: series["tasks"],
 	}, nil
-
 }
 
-// Equivalent to
-//
-//	aws cloudwatch get-metric-statistics \
-//					--namespace AWS/ECS \
-//					--metric-name CPUUtilization \
-//					--statistics Average \
-//					--start-time "$(date -u -v -30M +'%Y-%m-%dT%H:%M:%SZ')" \
-//					--end-time "$(date -u +'%Y-%m-%dT%H:%M:%SZ')" \
-//					--period 1800 \
-//					--dimensions Name=ClusterName,Value=${clusterName} Name=ServiceName,Value=${serviceName}
-//
-// Get last 30 minute, granularity 1800 seconds CPUUtilization
-func (store *Store) getCPU(cluster, service *string) ([]types.Datapoint, error) {
-	statisticsInput := store.getStatisticsInput(cluster, service)
-	statisticsInput.MetricName = aws.String(CPU)
-	metricOutput, err := store.cloudwatch.GetMetricStatistics(context.TODO(), statisticsInput)
+// Run metric data queries over the last duration, return series by query id
+func (store *Store) getMetricData(ctx context.Context, queries []types.MetricDataQuery, duration time.Duration) (map[string]MetricSeries, error) {
+	now := time.Now()
+	start := now.Add(-duration)
 
+	results, err := paginate(ctx, func(ctx context.Context, token *string) ([]types.MetricDataResult, *string, error) {
+		output, err := store.cloudwatch.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			StartTime:         aws.Time(start),
+			EndTime:           aws.Time(now),
+			ScanBy:            types.ScanByTimestampAscending,
+			NextToken:         token,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return output.MetricDataResults, output.NextToken, nil
+	})
 	if err != nil {
-		slog.Warn("failed to run aws api", "metrics", CPU, "cluster", *cluster, "service", *service, "error", err)
 		return nil, err
 	}
 
-	return metricOutput.Datapoints, nil
+	// values of one query continue on the next page
+	series := map[string]MetricSeries{}
+	for _, r := range results {
+		id := aws.ToString(r.Id)
+		s := series[id]
+		s.Timestamps = append(s.Timestamps, r.Timestamps...)
+		s.Values = append(s.Values, r.Values...)
+		series[id] = s
+	}
+	return series, nil
+}
+
+// Latest CPU and memory utilization percentage, nil when not reported
+type Utilization struct {
+	CPU    *float64
+	Memory *float64
+}
+
+// Utilization key and field filled by a metric data query
+type utilizationTarget struct {
+	key    string
+	memory bool
+}
+
+// Batched utilization queries of several resources
+type utilizationQueries struct {
+	queries []types.MetricDataQuery
+	targets map[string]utilizationTarget
+}
+
+func (q *utilizationQueries) add(id, key, namespace, name string, memory bool, dimensions []types.Dimension) {
+	if q.targets == nil {
+		q.targets = map[string]utilizationTarget{}
+	}
+	q.targets[id] = utilizationTarget{key: key, memory: memory}
+	q.queries = append(q.queries, types.MetricDataQuery{
+		Id: aws.String(id),
+		MetricStat: &types.MetricStat{
+			Metric: &types.Metric{
+				Namespace:  aws.String(namespace),
+				MetricName: aws.String(name),
+				Dimensions: dimensions,
+			},
+			Period: aws.Int32(60),
+			Stat:   aws.String(string(types.StatisticAverage)),
+		},
+	})
+}
+
+// Run queries of last 5 minutes in as few requests as possible, return latest values by key
+func (store *Store) getUtilization(ctx context.Context, q utilizationQueries) (map[string]Utilization, error) {
+	store.initCloudwatchClient()
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	utilization := map[string]Utilization{}
+	for chunk := range slices.Chunk(q.queries, maxMetricDataQueries) {
+		series, err := store.getMetricData(ctx, chunk, 5*time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		for id, s := range series {
+			last, ok := s.Last()
+			if !ok {
+				continue
+			}
+			target := q.targets[id]
+			u := utilization[target.key]
+			if target.memory {
+				u.Memory = &last
+			} else {
+				u.CPU = &last
+			}
+			utilization[target.key] = u
+		}
+	}
+	return utilization, nil
 }
 
 // Equivalent to
 //
-//	aws cloudwatch get-metric-statistics \
-//					--namespace AWS/ECS \
-//					--metric-name MemoryUtilization \
-//					--statistics Average \
-//					--start-time "$(date -u -v -30M +'%Y-%m-%dT%H:%M:%SZ')" \
-//					--end-time "$(date -u +'%Y-%m-%dT%H:%M:%SZ')" \
-//					--period 1800 \
-//					--dimensions Name=ClusterName,Value=${clusterName} Name=ServiceName,Value=${serviceName}
+//	aws cloudwatch get-metric-data \
+//		--start-time "$(date -u -v -5M +'%Y-%m-%dT%H:%M:%SZ')" \
+//		--end-time "$(date -u +'%Y-%m-%dT%H:%M:%SZ')" \
+//		--metric-data-queries '[{"Id":"s0_cpu","MetricStat":{"Metric":{"Namespace":"AWS/ECS","MetricName":"CPUUtilization","Dimensions":[{"Name":"ClusterName","Value":"${clusterName}"},{"Name":"ServiceName","Value":"${serviceName}"}]},"Period":60,"Stat":"Average"}}, ...]'
 //
-// Get last 30 minute, granularity 1800 seconds CPUUtilization
-func (store *Store) getMemory(cluster, service *string) ([]types.Datapoint, error) {
-	statisticsInput := store.getStatisticsInput(cluster, service)
-	statisticsInput.MetricName = aws.String(Memory)
-	metricOutput, err := store.cloudwatch.GetMetricStatistics(context.TODO(), statisticsInput)
+// Get latest utilization of services keyed by service name
+func (store *Store) GetServiceUtilization(ctx context.Context, cluster *string, services []string) (map[string]Utilization, error) {
+	q := utilizationQueries{}
+	for i, service := range services {
+		dimensions := []types.Dimension{
+			{Name: aws.String("ClusterName"), Value: cluster},
+			{Name: aws.String("ServiceName"), Value: aws.String(service)},
+		}
+		q.add(fmt.Sprintf("s%d_cpu", i), service, Namespace, CPU, false, dimensions)
+		q.add(fmt.Sprintf("s%d_mem", i), service, Namespace, Memory, true, dimensions)
+	}
 
+	utilization, err := store.getUtilization(ctx, q)
 	if err != nil {
-		slog.Warn("failed to run aws api", "metrics", Memory, "cluster", *cluster, "service", *service, "error", err)
+		slog.Warn("failed to run aws api", "metrics", "GetMetricData", "cluster", *cluster, "services", len(services), "error", err)
 		return nil, err
 	}
-
-	return metricOutput.Datapoints, nil
+	return utilization, nil
 }
 
-func (store *Store) getStatisticsInput(cluster, service *string) *cloudwatch.GetMetricStatisticsInput {
-	store.initCloudwatchClient()
-
-	// period := 30
-	// granularity := 1800
-	statistic := []types.Statistic{types.StatisticAverage}
-	halfHourAgo := time.Now().Add(-30 * time.Minute)
-	now := time.Now()
-	period := int32(1800)
-	dimensions := []types.Dimension{
-		{
-			Name:  aws.String("ClusterName"),
-			Value: cluster,
-		},
-		{
-			Name:  aws.String("ServiceName"),
-			Value: service,
-		},
+// Equivalent to
+//
+//	aws cloudwatch get-metric-data \
+//		--start-time "$(date -u -v -5M +'%Y-%m-%dT%H:%M:%SZ')" \
+//		--end-time "$(date -u +'%Y-%m-%dT%H:%M:%SZ')" \
+//		--metric-data-queries '[{"Id":"t0_cpu","MetricStat":{"Metric":{"Namespace":"ECS/ContainerInsights","MetricName":"TaskCpuUtilization","Dimensions":[{"Name":"ClusterName","Value":"${clusterName}"},{"Name":"TaskDefinitionFamily","Value":"${family}"},{"Name":"TaskId","Value":"${taskId}"}]},"Period":60,"Stat":"Average"}}, ...]'
+//
+// Get latest utilization of tasks and their containers from Container Insights enhanced observability,
+// keyed by task ID and "${taskId}/${containerName}"
+func (store *Store) GetTaskUtilization(ctx context.Context, cluster *string, tasks []ecsTypes.Task) (map[string]Utilization, error) {
+	q := utilizationQueries{}
+	for i, t := range tasks {
+		taskId := utils.ArnToName(t.TaskArn)
+		family := strings.Split(utils.ArnToName(t.TaskDefinitionArn), ":")[0]
+		dimensions := []types.Dimension{
+			{Name: aws.String("ClusterName"), Value: cluster},
+			{Name: aws.String("TaskDefinitionFamily"), Value: aws.String(family)},
+			{Name: aws.String("TaskId"), Value: aws.String(taskId)},
+		}
+		q.add(fmt.Sprintf("t%d_cpu", i), taskId, ContainerInsightsNamespace, TaskCPU, false, dimensions)
+		q.add(fmt.Sprintf("t%d_mem", i), taskId, ContainerInsightsNamespace, TaskMemory, true, dimensions)
+		for j, c := range t.Containers {
+			containerDimensions := append(slices.Clone(dimensions), types.Dimension{Name: aws.String("ContainerName"), Value: c.Name})
+			key := taskId + "/" + aws.ToString(c.Name)
+			q.add(fmt.Sprintf("t%d_c%d_cpu", i, j), key, ContainerInsightsNamespace, ContainerCPU, false, containerDimensions)
+			q.add(fmt.Sprintf("t%d_c%d_mem", i, j), key, ContainerInsightsNamespace, ContainerMemory, true, containerDimensions)
+		}
 	}
-	return &cloudwatch.GetMetricStatisticsInput{
-		MetricName: aws.String("CPUUtilization"),
-		Namespace:  aws.String("AWS/ECS"),
-		Statistics: statistic,
-		StartTime:  aws.Time(halfHourAgo),
-		EndTime:    aws.Time(now),
-		Period:     aws.Int32(period),
-		Dimensions: dimensions,
+
+	utilization, err := store.getUtilization(ctx, q)
+	if err != nil {
+		slog.Warn("failed to run aws api", "metrics", "GetMetricData", "cluster", *cluster, "tasks", len(tasks), "error", err)
+		return nil, err
 	}
+	return utilization, nil
 }
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestServiceMetrics(t *testing.T) {
+	fixtures, err := DemoFixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewFixtureStore(fixtures)
+	metrics, err := store.GetServiceMetrics(context.Background(), aws.String("demo-production"), aws.String("web"), MetricsWindows[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics.CPUAverage.Values) != 12 || len(metrics.RunningTaskCount.Values) != 12 {
+		t.Fatalf("Got %d CPU and %d task datapoints, Want: 12", len(metrics.CPUAverage.Values), len(metrics.RunningTaskCount.Values))
+	}
+	if tasks, _ := metrics.RunningTaskCount.Last(); tasks != 3 {
+		t.Errorf("Got %v running tasks, Want: 3", tasks)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	// Attempts of a throttled page before pagination gives up
+	pageMaxAttempts = 5
+	pageRetryDelay  = 500 * time.Millisecond
+)
+
+// Returned along with the items listed before pagination gave up
+type PartialError struct {
+	Err error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("partial results: %v", e.Err)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// Called before a throttled request is retried
+type RetryNotify func(attempt, maxAttempts int)
+
+type retryNotifyKey struct{}
+
+// Report retries of Store calls made with the returned context to notify
+func WithRetryNotify(ctx context.Context, notify RetryNotify) context.Context {
+	return context.WithValue(ctx, retryNotifyKey{}, notify)
+}
+
+// Wait before a throttled request is retried
+var retryAfter = time.After
+
+func notifyRetry(ctx context.Context, attempt, maxAttempts int) {
+	if notify, ok := ctx.Value(retryNotifyKey{}).(RetryNotify); ok {
+		notify(attempt, maxAttempts)
+	}
+}
+
+// Follow next tokens until the last page.
+// A throttled page is retried with exponential backoff up to pageMaxAttempts times.
+// Other errors and running out of attempts stop pagination, the error is returned as is
+// on the first page and as *PartialError with the items listed so far afterwards.
+func paginate[T any](ctx context.Context, fetch func(ctx context.Context, token *string) ([]T, *string, error)) ([]T, error) {
+	items := []T{}
+	var token *string
+	for page := 0; ; page++ {
+		results, next, err := fetchPage(ctx, token, fetch)
+		if err != nil {
+			if page == 0 || ctx.Err() != nil {
+				return nil, err
+			}
+			slog.Warn("stop pagination with partial results", "pages", page, "items", len(items), "error", err)
+			return items, &PartialError{Err: err}
+		}
+		items = append(items, results...)
+		if next == nil {
+			return items, nil
+		}
+		token = next
+	}
+}
+
+// Fetch one page, retry when throttled
+func fetchPage[T any](ctx context.Context, token *string, fetch func(ctx context.Context, token *string) ([]T, *string, error)) ([]T, *string, error) {
+	delay := pageRetryDelay
+	for attempt := 1; ; attempt++ {
+		results, next, err := fetch(ctx, token)
+		if err == nil || attempt == pageMaxAttempts || throttles.IsErrorThrottle(err) != aws.TrueTernary {
+			return results, next, err
+		}
+		slog.Warn("page throttled, retry", "attempt", attempt, "delay", delay, "error", err)
+		notifyRetry(ctx, attempt+1, pageMaxAttempts)
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-retryAfter(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Pages of items where a page fails with its error for the given number of attempts,
+// -1 fails every attempt. The token is the page index.
+type fakePages struct {
+	pages    [][]string
+	errs     map[int]error
+	failures map[int]int
+	attempts map[int]int
+}
+
+func (f *fakePages) fetch(ctx context.Context, token *string) ([]string, *string, error) {
+	page := 0
+	if token != nil {
+		page, _ = strconv.Atoi(*token)
+	}
+	f.attempts[page]++
+	if err := f.errs[page]; err != nil && (f.failures[page] < 0 || f.attempts[page] <= f.failures[page]) {
+		return nil, nil, err
+	}
+	var next *string
+	if page+1 < len(f.pages) {
+		next = aws.String(strconv.Itoa(page + 1))
+	}
+	return f.pages[page], next, nil
+}
+
+func TestPaginate(t *testing.T) {
+	errDenied := errors.New("AccessDeniedException")
+	pages := [][]string{{"a", "b"}, {"c"}, {"d", "e"}}
+	backoff := []time.Duration{pageRetryDelay, 2 * pageRetryDelay, 4 * pageRetryDelay, 8 * pageRetryDelay}
+
+	testCases := []struct {
+		name     string
+		errs     map[int]error
+		failures map[int]int
+		want     []string
+		partial  bool
+		wantErr  error
+		attempts map[int]int
+		delays   []time.Duration
+		notified []int
+	}{
+		{
+			name:     "all pages",
+			want:     []string{"a", "b", "c", "d", "e"},
+			attempts: map[int]int{0: 1, 1: 1, 2: 1},
+		},
+		{
+			name:     "throttled page retried with backoff",
+			errs:     map[int]error{1: errThrottled},
+			failures: map[int]int{1: 2},
+			want:     []string{"a", "b", "c", "d", "e"},
+			attempts: map[int]int{0: 1, 1: 3, 2: 1},
+			delays:   backoff[:2],
+			notified: []int{2, 3},
+		},
+		{
+			name:     "first page out of attempts",
+			errs:     map[int]error{0: errThrottled},
+			failures: map[int]int{0: -1},
+			wantErr:  errThrottled,
+			attempts: map[int]int{0: pageMaxAttempts},
+			delays:   backoff,
+			notified: []int{2, 3, 4, 5},
+		},
+		{
+			name:     "later page out of attempts",
+			errs:     map[int]error{2: errThrottled},
+			failures: map[int]int{2: -1},
+			want:     []string{"a", "b", "c"},
+			partial:  true,
+			wantErr:  errThrottled,
+			attempts: map[int]int{0: 1, 1: 1, 2: pageMaxAttempts},
+			delays:   backoff,
+			notified: []int{2, 3, 4, 5},
+		},
+		{
+			name:     "other error on first page",
+			errs:     map[int]error{0: errDenied},
+			failures: map[int]int{0: -1},
+			wantErr:  errDenied,
+			attempts: map[int]int{0: 1},
+		},
+		{
+			name:     "other error on later page",
+			errs:     map[int]error{1: errDenied},
+			failures: map[int]int{1: -1},
+			want:     []string{"a", "b"},
+			partial:  true,
+			wantErr:  errDenied,
+			attempts: map[int]int{0: 1, 1: 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delays := noRetryWait(t)
+			f := &fakePages{pages: pages, errs: tc.errs, failures: tc.failures, attempts: map[int]int{}}
+			notified := []int{}
+			ctx := WithRetryNotify(context.Background(), func(attempt, maxAttempts int) {
+				if maxAttempts != pageMaxAttempts {
+					t.Errorf("Got max attempts %d, Want: %d", maxAttempts, pageMaxAttempts)
+				}
+				notified = append(notified, attempt)
+			})
+
+			got, err := paginate(ctx, f.fetch)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Got error: %v, Want: %v", err, tc.wantErr)
+			}
+			var partial *PartialError
+			if errors.As(err, &partial) != tc.partial {
+				t.Errorf("Got error: %#v, Want: partial %t", err, tc.partial)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Got %v, Want: %v", got, tc.want)
+			}
+			for page, want := range tc.attempts {
+				if f.attempts[page] != want {
+					t.Errorf("Got %d attempts of page %d, Want: %d", f.attempts[page], page, want)
+				}
+			}
+			if !slices.Equal(*delays, tc.delays) {
+				t.Errorf("Got delays %v, Want: %v", *delays, tc.delays)
+			}
+			if !slices.Equal(notified, tc.notified) {
+				t.Errorf("Got notified attempts %v, Want: %v", notified, tc.notified)
+			}
+		})
+	}
+}
+
+func TestPaginateCancelled(t *testing.T) {
+	// waiting for a retry stops when the load is cancelled
+	retryAfter = func(time.Duration) <-chan time.Time { return nil }
+	defer func() { retryAfter = time.After }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &fakePages{
+		pages:    [][]string{{"a"}, {"b"}},
+		errs:     map[int]error{1: errThrottled},
+		failures: map[int]int{1: -1},
+		attempts: map[int]int{},
+	}
+	fetch := func(ctx context.Context, token *string) ([]string, *string, error) {
+		items, next, err := f.fetch(ctx, token)
+		if err != nil {
+			cancel()
+		}
+		return items, next, err
+	}
+	got, err := paginate(ctx, fetch)
+	if !errors.Is(err, context.Canceled) || got != nil {
+		t.Errorf("Got %v, error: %v, Want: cancelled without partial results", got, err)
+	}
+}
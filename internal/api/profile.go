@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Home returns the store of the current profile and region
+func (store *Store) Home() *Store {
+	root := store.rootStore()
+	if root.home != nil {
+		return root.home
+	}
+	return root
+}
+
+// Profile returns the AWS profile of the store, empty for the default chain
+func (store *Store) Profile() string {
+	return store.rootStore().profile
+}
+
+// ForProfile returns the store using profile credentials in the home region, created on first use
+func (store *Store) ForProfile(ctx context.Context, profile string) (*Store, error) {
+	home := store.Home()
+	if profile == home.profile {
+		return home, nil
+	}
+	if home.IsFixture() {
+		return nil, errFixtureUnsupported
+	}
+
+	home.profilesMu.Lock()
+	defer home.profilesMu.Unlock()
+	if s, ok := home.profiles[profile]; ok {
+		return s, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithSharedConfigProfile(profile), config.WithRegion(home.Region))
+	if err != nil {
+		slog.Warn("failed to load aws SDK config", "profile", profile, "error", err)
+		return nil, err
+	}
+	s := &Store{
+		Config:    &cfg,
+		cache:     newResponseCache(),
+		Timeout:   home.Timeout,
+		Endpoints: home.Endpoints,
+		profile:   profile,
+		home:      home,
+	}
+	s.ecs = s.newEcsClient(cfg)
+	if home.profiles == nil {
+		home.profiles = map[string]*Store{}
+	}
+	home.profiles[profile] = s
+	return s, nil
+}
+
+// Error of a call made with profile credentials
+type ProfileError struct {
+	Profile string
+	Err     error
+}
+
+func (e *ProfileError) Error() string {
+	return e.Profile + ": " + e.Err.Error()
+}
+
+func (e *ProfileError) Unwrap() error {
+	return e.Err
+}
+
+// Equivalent to
+// aws ecs list-clusters --profile ${profile}
+// aws ecs describe-clusters --profile ${profile} --clusters ${clusters}
+// for each profile at the same time, in each of regions when given.
+// Clusters are ordered by profiles and returned with the profile listing each of them, a cluster
+// reached by several profiles is listed once per profile. Profiles failing to list are reported
+// with *PartialError when others succeed.
+func (store *Store) ListClustersInProfiles(ctx context.Context, profiles []string, regions []string) ([]types.Cluster, []string, error) {
+	results := make([][]types.Cluster, len(profiles))
+	errs := make([]error, len(profiles))
+	var wg sync.WaitGroup
+	for i, profile := range profiles {
+		wg.Go(func() {
+			s, err := store.ForProfile(ctx, profile)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if len(regions) > 0 {
+				results[i], errs[i] = s.ListClustersInRegions(ctx, regions)
+			} else {
+				results[i], errs[i] = s.ListClusters(ctx)
+			}
+		})
+	}
+	wg.Wait()
+
+	clusters := []types.Cluster{}
+	clusterProfiles := []string{}
+	failed := []error{}
+	for i, profile := range profiles {
+		for range results[i] {
+			clusterProfiles = append(clusterProfiles, profile)
+		}
+		clusters = append(clusters, results[i]...)
+		if errs[i] != nil {
+			failed = append(failed, &ProfileError{Profile: profile, Err: errs[i]})
+		}
+	}
+	if len(failed) == 0 {
+		return clusters, clusterProfiles, nil
+	}
+	if len(clusters) == 0 {
+		return clusters, clusterProfiles, errors.Join(failed...)
+	}
+	return clusters, clusterProfiles, &PartialError{Err: errors.Join(failed...)}
+}
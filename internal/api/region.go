@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+func (store *Store) rootStore() *Store {
+	if store.root != nil {
+		return store.root
+	}
+	return store
+}
+
+// Regional returns the store serving region with the same credentials, created on first use
+func (store *Store) Regional(region string) *Store {
+	root := store.rootStore()
+	if region == "" || region == root.Region {
+		return root
+	}
+
+	root.regionalMu.Lock()
+	defer root.regionalMu.Unlock()
+	if s, ok := root.regional[region]; ok {
+		return s
+	}
+
+	var s *Store
+	if root.IsFixture() {
+		s = newFixtureRegionStore(root.ecs.(*fixtureEcs).fixtureBackend, region)
+	} else {
+		cfg := root.Config.Copy()
+		cfg.Region = region
+		s = &Store{
+			Config:    &cfg,
+			cache:     newResponseCache(),
+			Endpoints: root.Endpoints,
+		}
+		s.ecs = s.newEcsClient(cfg)
+	}
+	s.Timeout = root.Timeout
+	s.root = root
+	if root.regional == nil {
+		root.regional = map[string]*Store{}
+	}
+	root.regional[region] = s
+	return s
+}
+
+// Equivalent to
+// aws ecs list-clusters --region ${region}
+// aws ecs describe-clusters --region ${region} --clusters ${clusters}
+// for each region at the same time. Clusters are ordered by regions,
+// regions failing to list are reported with *PartialError when others succeed.
+func (store *Store) ListClustersInRegions(ctx context.Context, regions []string) ([]types.Cluster, error) {
+	results := make([][]types.Cluster, len(regions))
+	errs := make([]error, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Go(func() {
+			results[i], errs[i] = store.Regional(region).ListClusters(ctx)
+		})
+	}
+	wg.Wait()
+
+	clusters := []types.Cluster{}
+	failed := []error{}
+	for i, region := range regions {
+		clusters = append(clusters, results[i]...)
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", region, errs[i]))
+		}
+	}
+	if len(failed) == 0 {
+		return clusters, nil
+	}
+	if len(clusters) == 0 {
+		return clusters, errors.Join(failed...)
+	}
+	return clusters, &PartialError{Err: errors.Join(failed...)}
+}
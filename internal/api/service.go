@@ -3,103 +3,76 @@ package api
 import (
 	"context"
 	"log/slog"
-	"math"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
-	"golang.org/x/sync/errgroup"
 )
 
 // Equivalent to
 // aws ecs list-services --cluster ${cluster}
 // aws ecs describe-services --cluster ${cluster} --services ${service}
-func (store *Store) ListServices(clusterName *string) ([]types.Service, error) {
-	// You may specify up to 100 services to describe.
-	// If there are > 100 services in the cluster, loop and slice by 100
-	// to describe them in batches of <= 100.
-	limit := int32(100)
-	params := &ecs.ListServicesInput{
-		Cluster:    clusterName,
-		MaxResults: &limit,
-	}
+func (store *Store) ListServices(ctx context.Context, clusterName *string) ([]types.Service, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
 
-	serviceARNs := []string{}
+	return cached(ctx, store, cacheServices, aws.ToString(clusterName), func(ctx context.Context) ([]types.Service, error) {
+		return store.listServices(ctx, clusterName)
+	})
+}
 
-	for {
-		listServicesOutput, err := store.ecs.ListServices(context.Background(), params)
+func (store *Store) listServices(ctx context.Context, clusterName *string) ([]types.Service, error) {
+	limit := int32(100)
+	serviceARNs, listErr := paginate(ctx, func(ctx context.Context, token *string) ([]string, *string, error) {
+		listServicesOutput, err := store.ecs.ListServices(ctx, &ecs.ListServicesInput{
+			Cluster:    clusterName,
+			MaxResults: &limit,
+			NextToken:  token,
+		})
 		if err != nil {
 			slog.Warn("failed to run aws api to list services", "error", err)
-			// If first run failed return err
-			if len(serviceARNs) == 0 {
-				return []types.Service{}, err
-			}
-			continue
-		}
-
-		if len(listServicesOutput.ServiceArns) == 0 {
-			return nil, nil
+			return nil, nil, err
 		}
+		return listServicesOutput.ServiceArns, listServicesOutput.NextToken, nil
+	})
+	if listErr != nil && len(serviceARNs) == 0 {
+		return []types.Service{}, listErr
+	}
 
-		serviceARNs = append(serviceARNs, listServicesOutput.ServiceArns...)
-
-		if listServicesOutput.NextToken != nil {
-			params.NextToken = listServicesOutput.NextToken
-		} else {
-			break
-		}
+	if len(serviceARNs) == 0 {
+		return nil, nil
 	}
 
 	// DescribeService api limit is 10
 	// InvalidParameterException: service names can have at most 10 items
-	batchSize := 10
-	serviceCount := len(serviceARNs)
-	loopCount := serviceCount / batchSize
-
-	// If the number of services is divisible by batchSize, it's necessary to loop one less
-	// time to describe all services in batches of batchSize.
-	// Otherwise, we'll attempt to describe an empty slice of services, which results in a
-	// HTTP 400: InvalidParameterException: Services cannot be empty.
-	if serviceCount%batchSize == 0 {
-		loopCount = loopCount - 1
-	}
-
-	results := []types.Service{}
-	g := new(errgroup.Group)
-
-	for i := 0; i <= loopCount; i++ {
-		i := i
-
-		g.Go(func() error {
-			services := serviceARNs[i*batchSize : int(math.Min(float64((i+1)*batchSize), float64(serviceCount)))]
-
-			describeServicesOutput, err := store.ecs.DescribeServices(context.Background(), &ecs.DescribeServicesInput{
-				Services: services,
-				Cluster:  clusterName,
-				Include: []types.ServiceField{
-					types.ServiceFieldTags,
-				},
-			})
-			if err != nil {
-				slog.Warn("failed to run aws api to describe services", "i times loop", i, "error", err)
-				return err
-			}
-
-			results = append(results, describeServicesOutput.Services...)
-
-			return nil
+	results, err := fetchBatches(ctx, serviceARNs, 10, func(ctx context.Context, services []string) ([]types.Service, error) {
+		describeServicesOutput, err := store.ecs.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Services: services,
+			Cluster:  clusterName,
+			Include: []types.ServiceField{
+				types.ServiceFieldTags,
+			},
 		})
-	}
-
-	if err := g.Wait(); err != nil {
+		if err != nil {
+			slog.Warn("failed to run aws api to describe services", "error", err)
+			return nil, err
+		}
+		return describeServicesOutput.Services, nil
+	})
+	if err != nil {
 		return []types.Service{}, err
 	}
 
-	return results, nil
+	// Partial list error, if any, tells the UI not all services are shown
+	return results, listErr
 }
 
 // Equivalent to
 // aws ecs update-service --cluster ${cluster} --service ${service} --task-definition ${task-definition} --desired-count ${count} --force-new-deployment
-func (store *Store) UpdateService(input *ecs.UpdateServiceInput) (*types.Service, error) {
+func (store *Store) UpdateService(ctx context.Context, input *ecs.UpdateServiceInput) (*types.Service, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	taskDefinition := "no task definition"
 	if input.TaskDefinition != nil {
 		taskDefinition = *input.TaskDefinition
@@ -126,24 +99,28 @@ func (store *Store) UpdateService(input *ecs.UpdateServiceInput) (*types.Service
 		),
 	)
 
-	updateOutput, err := store.ecs.UpdateService(context.Background(), input)
+	updateOutput, err := store.ecs.UpdateService(ctx, input)
 	if err != nil {
 		slog.Warn("failed to run aws api to update service", "error", err)
 		return nil, err
 	}
+	store.cache.invalidate()
 	return updateOutput.Service, nil
 }
 
 // Equivalent to
 // aws ecs stop-service-deployment --service-deployment-arn ${service-deployment-arn} --stop-type ROLLBACK
-func (store *Store) RollbackServiceDeployment(serviceDeploymentArn *string) error {
+func (store *Store) RollbackServiceDeployment(ctx context.Context, serviceDeploymentArn *string) error {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	slog.Info("rollback service deployment",
 		slog.Group("parameters",
 			slog.String("serviceDeploymentArn", *serviceDeploymentArn),
 		),
 	)
 
-	_, err := store.ecs.StopServiceDeployment(context.Background(), &ecs.StopServiceDeploymentInput{
+	_, err := store.ecs.StopServiceDeployment(ctx, &ecs.StopServiceDeploymentInput{
 		ServiceDeploymentArn: serviceDeploymentArn,
 		StopType:             types.StopServiceDeploymentStopTypeRollback,
 	})
@@ -151,6 +128,7 @@ func (store *Store) RollbackServiceDeployment(serviceDeploymentArn *string) erro
 		slog.Warn("failed to run aws api to rollback service deployment", "error", err)
 		return err
 	}
+	store.cache.invalidate()
 
 	return nil
 }
@@ -11,7 +11,10 @@ import (
 // Equivalent to
 // aws ecs list-service-deployments --cluster ${cluster} --service ${service}
 // aws ecs describe-service-deployments --service-deployment-arns ${arn1} ${arn2}
-func (store *Store) ListServiceDeployments(cluster, service *string) ([]types.ServiceDeployment, error) {
+func (store *Store) ListServiceDeployments(ctx context.Context, cluster, service *string) ([]types.ServiceDeployment, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	batchSize := 20
 	limit := int32(batchSize)
 	deploymentARNs := []string{}
@@ -21,7 +24,7 @@ func (store *Store) ListServiceDeployments(cluster, service *string) ([]types.Se
 		MaxResults: &limit,
 	}
 
-	listServiceDeploymentsOutput, err := store.ecs.ListServiceDeployments(context.Background(), params)
+	listServiceDeploymentsOutput, err := store.ecs.ListServiceDeployments(ctx, params)
 	if err != nil {
 		slog.Warn("failed to run aws api to list service deployments", "error", err)
 		return []types.ServiceDeployment{}, err
@@ -31,7 +34,7 @@ func (store *Store) ListServiceDeployments(cluster, service *string) ([]types.Se
 		deploymentARNs = append(deploymentARNs, *deployment.ServiceDeploymentArn)
 	}
 
-	describeOutput, err := store.ecs.DescribeServiceDeployments(context.Background(), &ecs.DescribeServiceDeploymentsInput{
+	describeOutput, err := store.ecs.DescribeServiceDeployments(ctx, &ecs.DescribeServiceDeploymentsInput{
 		ServiceDeploymentArns: deploymentARNs,
 	})
 
@@ -45,8 +48,11 @@ func (store *Store) ListServiceDeployments(cluster, service *string) ([]types.Se
 
 // Equivalent to
 // aws ecs describe-service-revisions --service-revision-arns ${arn1}
-func (store *Store) GetServiceRevision(serviceRevisionArn *string) (*types.ServiceRevision, error) {
-	describeServiceRevisionOutput, err := store.ecs.DescribeServiceRevisions(context.Background(), &ecs.DescribeServiceRevisionsInput{
+func (store *Store) GetServiceRevision(ctx context.Context, serviceRevisionArn *string) (*types.ServiceRevision, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	describeServiceRevisionOutput, err := store.ecs.DescribeServiceRevisions(ctx, &ecs.DescribeServiceRevisionsInput{
 		ServiceRevisionArns: []string{*serviceRevisionArn},
 	})
 	if err != nil {
@@ -21,6 +21,8 @@ type SsmStartSessionInput struct {
 	Host        string
 	Port        string
 	LocalPort   string
+	// Partition of the task ARN, aws when empty
+	Partition string
 }
 
 // Equivalent to
@@ -33,7 +35,10 @@ type SsmStartSessionInput struct {
 // --target ecs:${cluster_id}_${task_id}_${runtime_id}
 // --document-name AWS-StartPortForwardingSession
 // --parameters {"portNumber":["${port}"], "localPortNumber":["${local_port}"]}
-func (store *Store) StartSession(input *SsmStartSessionInput, profile string, region string) (*string, error) {
+func (store *Store) StartSession(ctx context.Context, input *SsmStartSessionInput, profile string, region string) (*string, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	store.initSsmClient()
 	smpCi := "session-manager-plugin"
 
@@ -56,7 +61,7 @@ func (store *Store) StartSession(input *SsmStartSessionInput, profile string, re
 		Reason:       aws.String("session started via e1s"),
 	}
 
-	result, err := store.ssm.StartSession(context.Background(), startInput)
+	result, err := store.ssm.StartSession(ctx, startInput)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +88,7 @@ func (store *Store) StartSession(input *SsmStartSessionInput, profile string, re
 		"StartSession",
 		profile,
 		string(parameterJson),
-		fmt.Sprintf("https://ssm.%v.amazonaws.com", region),
+		store.ssmEndpoint(region, input.Partition),
 	}
 
 	slog.Info("exec", "command", bin+" "+strings.Join(args, " "))
@@ -94,7 +99,10 @@ func (store *Store) StartSession(input *SsmStartSessionInput, profile string, re
 	return result.SessionId, err
 }
 
-func (store *Store) TerminateSessions(sessionIds []*string) error {
+func (store *Store) TerminateSessions(ctx context.Context, sessionIds []*string) error {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	store.initSsmClient()
 	g := new(errgroup.Group)
 
@@ -104,7 +112,7 @@ func (store *Store) TerminateSessions(sessionIds []*string) error {
 			input := &ssm.TerminateSessionInput{
 				SessionId: id,
 			}
-			_, err := store.ssm.TerminateSession(context.Background(), input)
+			_, err := store.ssm.TerminateSession(ctx, input)
 			return err
 		})
 	}
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -11,59 +13,205 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+// Subset of the ECS client used by Store
+type ecsAPI interface {
+	ListClusters(context.Context, *ecs.ListClustersInput, ...func(*ecs.Options)) (*ecs.ListClustersOutput, error)
+	DescribeClusters(context.Context, *ecs.DescribeClustersInput, ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error)
+	ListServices(context.Context, *ecs.ListServicesInput, ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
+	DescribeServices(context.Context, *ecs.DescribeServicesInput, ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	UpdateService(context.Context, *ecs.UpdateServiceInput, ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error)
+	ListServiceDeployments(context.Context, *ecs.ListServiceDeploymentsInput, ...func(*ecs.Options)) (*ecs.ListServiceDeploymentsOutput, error)
+	DescribeServiceDeployments(context.Context, *ecs.DescribeServiceDeploymentsInput, ...func(*ecs.Options)) (*ecs.DescribeServiceDeploymentsOutput, error)
+	StopServiceDeployment(context.Context, *ecs.StopServiceDeploymentInput, ...func(*ecs.Options)) (*ecs.StopServiceDeploymentOutput, error)
+	DescribeServiceRevisions(context.Context, *ecs.DescribeServiceRevisionsInput, ...func(*ecs.Options)) (*ecs.DescribeServiceRevisionsOutput, error)
+	ListTasks(context.Context, *ecs.ListTasksInput, ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+	DescribeTasks(context.Context, *ecs.DescribeTasksInput, ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	StopTask(context.Context, *ecs.StopTaskInput, ...func(*ecs.Options)) (*ecs.StopTaskOutput, error)
+	DescribeTaskDefinition(context.Context, *ecs.DescribeTaskDefinitionInput, ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+	ListTaskDefinitions(context.Context, *ecs.ListTaskDefinitionsInput, ...func(*ecs.Options)) (*ecs.ListTaskDefinitionsOutput, error)
+	ListTaskDefinitionFamilies(context.Context, *ecs.ListTaskDefinitionFamiliesInput, ...func(*ecs.Options)) (*ecs.ListTaskDefinitionFamiliesOutput, error)
+	RegisterTaskDefinition(context.Context, *ecs.RegisterTaskDefinitionInput, ...func(*ecs.Options)) (*ecs.RegisterTaskDefinitionOutput, error)
+	ListContainerInstances(context.Context, *ecs.ListContainerInstancesInput, ...func(*ecs.Options)) (*ecs.ListContainerInstancesOutput, error)
+	DescribeContainerInstances(context.Context, *ecs.DescribeContainerInstancesInput, ...func(*ecs.Options)) (*ecs.DescribeContainerInstancesOutput, error)
+}
+
+// Subset of the CloudWatch client used by Store
+type cloudwatchAPI interface {
+	GetMetricData(context.Context, *cloudwatch.GetMetricDataInput, ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// Subset of the CloudWatch Logs client used by Store
+type cloudwatchlogsAPI interface {
+	DescribeLogStreams(context.Context, *cloudwatchlogs.DescribeLogStreamsInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error)
+	GetLogEvents(context.Context, *cloudwatchlogs.GetLogEventsInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error)
+	FilterLogEvents(context.Context, *cloudwatchlogs.FilterLogEventsInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	StartQuery(context.Context, *cloudwatchlogs.StartQueryInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error)
+	GetQueryResults(context.Context, *cloudwatchlogs.GetQueryResultsInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error)
+	StopQuery(context.Context, *cloudwatchlogs.StopQueryInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StopQueryOutput, error)
+}
+
+// Subset of the Application Auto Scaling client used by Store
+type autoScalingAPI interface {
+	DescribeScalableTargets(context.Context, *applicationautoscaling.DescribeScalableTargetsInput, ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalableTargetsOutput, error)
+	DescribeScalingPolicies(context.Context, *applicationautoscaling.DescribeScalingPoliciesInput, ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalingPoliciesOutput, error)
+	DescribeScalingActivities(context.Context, *applicationautoscaling.DescribeScalingActivitiesInput, ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalingActivitiesOutput, error)
+	DescribeScheduledActions(context.Context, *applicationautoscaling.DescribeScheduledActionsInput, ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScheduledActionsOutput, error)
+}
+
+// Subset of the SSM client used by Store
+type ssmAPI interface {
+	StartSession(context.Context, *ssm.StartSessionInput, ...func(*ssm.Options)) (*ssm.StartSessionOutput, error)
+	TerminateSession(context.Context, *ssm.TerminateSessionInput, ...func(*ssm.Options)) (*ssm.TerminateSessionOutput, error)
+}
+
+// Subset of the Account client used by Store
+type accountAPI interface {
+	ListRegions(context.Context, *account.ListRegionsInput, ...func(*account.Options)) (*account.ListRegionsOutput, error)
+}
+
+// Subset of the IAM client used by Store
+type iamAPI interface {
+	ListAccountAliases(context.Context, *iam.ListAccountAliasesInput, ...func(*iam.Options)) (*iam.ListAccountAliasesOutput, error)
+}
+
+// Subset of the STS client used by Store
+type stsAPI interface {
+	GetCallerIdentity(context.Context, *sts.GetCallerIdentityInput, ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
 type Store struct {
 	*aws.Config
-	ecs            *ecs.Client
-	cloudwatch     *cloudwatch.Client
-	cloudwatchlogs *cloudwatchlogs.Client
-	autoScaling    *applicationautoscaling.Client
-	ssm            *ssm.Client
-	account        *account.Client
+	ecs            ecsAPI
+	cloudwatch     cloudwatchAPI
+	cloudwatchlogs cloudwatchlogsAPI
+	autoScaling    autoScalingAPI
+	ssm            ssmAPI
+	account        accountAPI
+	iam            iamAPI
+	sts            stsAPI
+	// Upper bound of each Store call, zero means no timeout
+	Timeout time.Duration
+	// Non nil when the store is served from a fixture snapshot instead of AWS
+	fixtures *Fixtures
+	cache    *responseCache
+
+	// Store the regional store was created from, nil for the store of the current region
+	root       *Store
+	regionalMu sync.Mutex
+	regional   map[string]*Store
+
+	// Service endpoint overrides, shared by regional and profile stores
+	Endpoints Endpoints
+
+	// AWS profile credentials are loaded from, empty for the default chain
+	profile string
+	// Store the profile store was created from, nil for the store of the current profile
+	home       *Store
+	profilesMu sync.Mutex
+	profiles   map[string]*Store
 }
 
-func NewStore(profile string, region string) (*Store, error) {
+func NewStore(profile string, region string, endpoints Endpoints) (*Store, error) {
 	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
 	if err != nil {
 		slog.Error("failed to load aws SDK config", "error", err)
 		return nil, err
 	}
-	ecsClient := ecs.NewFromConfig(cfg)
-	slog.Info("load config", slog.String("AWS_PROFILE", profile), slog.String("AWS_REGION", cfg.Region))
-	return &Store{
-		Config: &cfg,
-		ecs:    ecsClient,
-	}, nil
+	slog.Info("load config", slog.String("AWS_PROFILE", profile), slog.String("AWS_REGION", cfg.Region), slog.String("endpoint", endpoints.URL))
+	store := &Store{
+		Config:    &cfg,
+		cache:     newResponseCache(),
+		profile:   profile,
+		Endpoints: endpoints,
+	}
+	store.ecs = store.newEcsClient(cfg)
+	return store, nil
+}
+
+// IsFixture reports whether the store is backed by a fixture snapshot
+func (store *Store) IsFixture() bool {
+	return store.fixtures != nil
+}
+
+// Bound ctx by the store timeout
+func (store *Store) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if store.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, store.Timeout)
 }
 
 func (store *Store) initCloudwatchClient() {
 	if store.cloudwatch == nil {
-		store.cloudwatch = cloudwatch.NewFromConfig(*store.Config)
+		store.cloudwatch = cloudwatch.NewFromConfig(*store.Config, func(o *cloudwatch.Options) {
+			if e := store.endpoint("cloudwatch"); e != nil {
+				o.BaseEndpoint = e
+			}
+		})
 	}
 }
 
 func (store *Store) initCloudwatchlogsClient() {
 	if store.cloudwatchlogs == nil {
-		store.cloudwatchlogs = cloudwatchlogs.NewFromConfig(*store.Config)
+		store.cloudwatchlogs = cloudwatchlogs.NewFromConfig(*store.Config, func(o *cloudwatchlogs.Options) {
+			if e := store.endpoint("logs"); e != nil {
+				o.BaseEndpoint = e
+			}
+		})
 	}
 }
 
 func (store *Store) initSsmClient() {
 	if store.ssm == nil {
-		store.ssm = ssm.NewFromConfig(*store.Config)
+		store.ssm = ssm.NewFromConfig(*store.Config, func(o *ssm.Options) {
+			if e := store.endpoint("ssm"); e != nil {
+				o.BaseEndpoint = e
+			}
+		})
 	}
 }
 
 func (store *Store) initAccountClient() {
 	if store.account == nil {
-		store.account = account.NewFromConfig(*store.Config)
+		store.account = account.NewFromConfig(*store.Config, func(o *account.Options) {
+			if e := store.endpoint("account"); e != nil {
+				o.BaseEndpoint = e
+			}
+		})
+	}
+}
+
+func (store *Store) initIamClient() {
+	if store.iam == nil {
+		store.iam = iam.NewFromConfig(*store.Config, func(o *iam.Options) {
+			if e := store.endpoint("iam"); e != nil {
+				o.BaseEndpoint = e
+			}
+		})
+	}
+}
+
+func (store *Store) initStsClient() {
+	if store.sts == nil {
+		store.sts = sts.NewFromConfig(*store.Config, func(o *sts.Options) {
+			if e := store.endpoint("sts"); e != nil {
+				o.BaseEndpoint = e
+			}
+		})
 	}
 }
 
 func (store *Store) initAutoScalingClient() {
 	if store.autoScaling == nil {
-		store.autoScaling = applicationautoscaling.NewFromConfig(*store.Config)
+		store.autoScaling = applicationautoscaling.NewFromConfig(*store.Config, func(o *applicationautoscaling.Options) {
+			if e := store.endpoint("application-autoscaling"); e != nil {
+				o.BaseEndpoint = e
+			}
+		})
 	}
 }
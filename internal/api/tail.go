@@ -0,0 +1,259 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/utils"
+)
+
+const (
+	// Interval of FilterLogEvents polling when Live Tail is unavailable
+	logPollInterval = 2 * time.Second
+	// FilterLogEvents and StartLiveTail limit of log stream names
+	maxLogStreamNames = 100
+)
+
+var errLiveTailUnsupported = errors.New("live tail not supported")
+
+type liveTailAPI interface {
+	StartLiveTail(context.Context, *cloudwatchlogs.StartLiveTailInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartLiveTailOutput, error)
+}
+
+// Log group and its log streams to read, all streams of group when Streams is empty
+type LogTarget struct {
+	Group string
+	// Log group ARN, Live Tail only accepts ARNs
+	Arn string
+	// Region of the log group, from the awslogs-region option
+	Region  string
+	Streams []string
+}
+
+// Log event of a tail
+type LogEvent struct {
+	Timestamp time.Time
+	Group     string
+	Stream    string
+	Message   string
+}
+
+// Get awslogs log groups of task definition containers, grouped by log group.
+// Streams are set to the streams of taskId when not empty, containerName selects one container when not empty.
+func (store *Store) GetLogTargets(ctx context.Context, tdArn *string, taskId, containerName string) ([]LogTarget, error) {
+	td, err := store.DescribeTaskDefinition(ctx, tdArn)
+	if err != nil {
+		slog.Warn("failed to run aws api to describe task definition", "error", err)
+		return nil, err
+	}
+
+	targets := []LogTarget{}
+	for _, c := range td.ContainerDefinitions {
+		if containerName != "" && aws.ToString(c.Name) != containerName {
+			continue
+		}
+		if c.LogConfiguration == nil || c.LogConfiguration.LogDriver != types.LogDriverAwslogs {
+			continue
+		}
+		group := c.LogConfiguration.Options["awslogs-group"]
+		if group == "" {
+			continue
+		}
+		region := c.LogConfiguration.Options["awslogs-region"]
+		if region == "" {
+			region = store.Region
+		}
+		arn := fmt.Sprintf("arn:%s:logs:%s:%s:log-group:%s", utils.ArnToPartition(tdArn), region, utils.ArnToAccount(tdArn), group)
+
+		i := slices.IndexFunc(targets, func(t LogTarget) bool { return t.Arn == arn })
+		if i < 0 {
+			targets = append(targets, LogTarget{
+				Group:  group,
+				Arn:    arn,
+				Region: region,
+			})
+			i = len(targets) - 1
+		}
+		if taskId == "" {
+			continue
+		}
+		streamPrefix := aws.ToString(c.Name)
+		if prefix, ok := c.LogConfiguration.Options["awslogs-stream-prefix"]; ok {
+			streamPrefix = prefix
+		}
+		targets[i].Streams = append(targets[i].Streams, fmt.Sprintf("%s/%s/%s", streamPrefix, aws.ToString(c.Name), taskId))
+	}
+	return targets, nil
+}
+
+// Equivalent to
+//
+//	aws logs start-live-tail \
+//	  --log-group-identifiers "$log_group_arn" \
+//	  --log-stream-names "$stream_prefix/$container_name/$task_id"
+//
+// Send new log events of targets to events until ctx is done. Each target streams in its own
+// Live Tail session, so several log groups and their streams can be followed together,
+// and falls back to polling FilterLogEvents when Live Tail is unavailable or its session ends.
+// Log groups of another region are read with a store of their region.
+func (store *Store) TailLogs(ctx context.Context, targets []LogTarget, events chan<- LogEvent) {
+	since := time.Now()
+
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		s := store
+		if target.Region != "" && target.Region != store.Region {
+			s = store.Regional(target.Region)
+		}
+		s.initCloudwatchlogsClient()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var latest time.Time
+			err := s.liveTail(ctx, target, events, &latest)
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("live tail unavailable, poll log events", "logGroup", target.Group, "error", err)
+			s.pollLogEvents(ctx, target, pollSince(since, latest), events)
+		}()
+	}
+	wg.Wait()
+}
+
+// Polling after a Live Tail session starts after the latest event it sent
+func pollSince(since, latest time.Time) time.Time {
+	if latest.IsZero() {
+		return since
+	}
+	return latest.Add(time.Millisecond)
+}
+
+// Stream events of target until the session ends, latest is set to the latest event timestamp sent
+func (store *Store) liveTail(ctx context.Context, target LogTarget, events chan<- LogEvent, latest *time.Time) error {
+	client, ok := store.cloudwatchlogs.(liveTailAPI)
+	if !ok {
+		return errLiveTailUnsupported
+	}
+	input := &cloudwatchlogs.StartLiveTailInput{
+		LogGroupIdentifiers: []string{target.Arn},
+	}
+	if len(target.Streams) > 0 {
+		input.LogStreamNames = target.Streams[:min(len(target.Streams), maxLogStreamNames)]
+	}
+	output, err := client.StartLiveTail(ctx, input)
+	if err != nil {
+		return err
+	}
+	stream := output.GetStream()
+	defer stream.Close()
+	return forwardLiveTail(ctx, target, stream, events, latest)
+}
+
+func forwardLiveTail(ctx context.Context, target LogTarget, stream cloudwatchlogs.StartLiveTailResponseStreamReader, events chan<- LogEvent, latest *time.Time) error {
+	for e := range stream.Events() {
+		update, ok := e.(*cloudwatchlogsTypes.StartLiveTailResponseStreamMemberSessionUpdate)
+		if !ok {
+			continue
+		}
+		for _, r := range update.Value.SessionResults {
+			event := LogEvent{
+				Timestamp: time.UnixMilli(aws.ToInt64(r.Timestamp)),
+				Group:     target.Group,
+				Stream:    aws.ToString(r.LogStreamName),
+				Message:   aws.ToString(r.Message),
+			}
+			select {
+			case events <- event:
+				if event.Timestamp.After(*latest) {
+					*latest = event.Timestamp
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	return errors.New("live tail session ended")
+}
+
+// Equivalent to
+//
+//	aws logs filter-log-events \
+//	  --log-group-name "$log_group" \
+//	  --log-stream-names "$stream_prefix/$container_name/$task_id" \
+//	  --start-time "$since"
+//
+// Poll events after since every logPollInterval until ctx is done
+func (store *Store) pollLogEvents(ctx context.Context, target LogTarget, since time.Time, events chan<- LogEvent) {
+	start := since.UnixMilli()
+	// events of the start millisecond already sent
+	seen := map[string]bool{}
+	for {
+		filtered, err := paginate(ctx, func(ctx context.Context, token *string) ([]cloudwatchlogsTypes.FilteredLogEvent, *string, error) {
+			ctx, cancel := store.withTimeout(ctx)
+			defer cancel()
+			input := &cloudwatchlogs.FilterLogEventsInput{
+				LogGroupName: aws.String(target.Group),
+				StartTime:    aws.Int64(start),
+				NextToken:    token,
+			}
+			if len(target.Streams) > 0 {
+				input.LogStreamNames = target.Streams[:min(len(target.Streams), maxLogStreamNames)]
+			}
+			output, err := store.cloudwatchlogs.FilterLogEvents(ctx, input)
+			if err != nil {
+				return nil, nil, err
+			}
+			return output.Events, output.NextToken, nil
+		})
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("failed to run aws api to filter log events", "logGroup", target.Group, "error", err)
+		}
+
+		slices.SortStableFunc(filtered, func(a, b cloudwatchlogsTypes.FilteredLogEvent) int {
+			return cmp.Compare(aws.ToInt64(a.Timestamp), aws.ToInt64(b.Timestamp))
+		})
+		for _, e := range filtered {
+			timestamp := aws.ToInt64(e.Timestamp)
+			id := aws.ToString(e.EventId)
+			if timestamp < start || seen[id] {
+				continue
+			}
+			if timestamp > start {
+				start = timestamp
+				clear(seen)
+			}
+			seen[id] = true
+			event := LogEvent{
+				Timestamp: time.UnixMilli(timestamp),
+				Group:     target.Group,
+				Stream:    aws.ToString(e.LogStreamName),
+				Message:   aws.ToString(e.Message),
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-time.After(logPollInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Live Tail stream sending its events and ending
+type fakeLiveTail struct {
+	events chan cloudwatchlogsTypes.StartLiveTailResponseStream
+}
+
+func (f *fakeLiveTail) Events() <-chan cloudwatchlogsTypes.StartLiveTailResponseStream {
+	return f.events
+}
+
+func (f *fakeLiveTail) Close() error { return nil }
+
+func (f *fakeLiveTail) Err() error { return nil }
+
+// Log group with events at the given unix milliseconds
+type fakeLogEvents struct {
+	cloudwatchlogsAPI
+	timestamps []int64
+}
+
+func (f *fakeLogEvents) FilterLogEvents(ctx context.Context, input *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	output := &cloudwatchlogs.FilterLogEventsOutput{}
+	for _, ts := range f.timestamps {
+		if ts < aws.ToInt64(input.StartTime) {
+			continue
+		}
+		output.Events = append(output.Events, cloudwatchlogsTypes.FilteredLogEvent{
+			EventId:   aws.String(time.UnixMilli(ts).String()),
+			Timestamp: aws.Int64(ts),
+		})
+	}
+	return output, nil
+}
+
+func TestPollAfterLiveTailEnds(t *testing.T) {
+	target := LogTarget{Group: "/ecs/app"}
+	stream := &fakeLiveTail{events: make(chan cloudwatchlogsTypes.StartLiveTailResponseStream, 1)}
+	stream.events <- &cloudwatchlogsTypes.StartLiveTailResponseStreamMemberSessionUpdate{
+		Value: cloudwatchlogsTypes.LiveTailSessionUpdate{
+			SessionResults: []cloudwatchlogsTypes.LiveTailSessionLogEvent{
+				{Timestamp: aws.Int64(1005)},
+				{Timestamp: aws.Int64(1000)},
+			},
+		},
+	}
+	close(stream.events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan LogEvent, 10)
+	var latest time.Time
+	if err := forwardLiveTail(ctx, target, stream, events, &latest); err == nil {
+		t.Fatal("Got no error, Want: live tail session ended")
+	}
+	if !latest.Equal(time.UnixMilli(1005)) {
+		t.Errorf("Got latest %v, Want: %v", latest, time.UnixMilli(1005))
+	}
+
+	// events already tailed are not polled again
+	store := &Store{cloudwatchlogs: &fakeLogEvents{timestamps: []int64{1000, 1005, 1010, 1020}}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		store.pollLogEvents(ctx, target, pollSince(time.UnixMilli(900), latest), events)
+	}()
+	got := []int64{}
+	for len(got) < 4 {
+		got = append(got, (<-events).Timestamp.UnixMilli())
+	}
+	cancel()
+	<-done
+	want := []int64{1005, 1000, 1010, 1020}
+	if !slices.Equal(got, want) {
+		t.Errorf("Got event timestamps %v, Want: %v", got, want)
+	}
+
+	if got := pollSince(time.UnixMilli(900), time.Time{}); !got.Equal(time.UnixMilli(900)) {
+		t.Errorf("Got poll since %v, Want: start of the tail when nothing was tailed", got)
+	}
+}
+
+func TestLogTargetsRegion(t *testing.T) {
+	awslogs := func(options map[string]string) *types.LogConfiguration {
+		return &types.LogConfiguration{LogDriver: types.LogDriverAwslogs, Options: options}
+	}
+	tdArn := aws.String("arn:aws:ecs:us-east-1:111111:task-definition/app:1")
+	store := NewFixtureStore(&Fixtures{
+		Region: "us-east-1",
+		TaskDefinitions: []types.TaskDefinition{{
+			TaskDefinitionArn: tdArn,
+			ContainerDefinitions: []types.ContainerDefinition{
+				{Name: aws.String("app"), LogConfiguration: awslogs(map[string]string{"awslogs-group": "/ecs/app", "awslogs-region": "eu-west-1"})},
+				{Name: aws.String("sidecar"), LogConfiguration: awslogs(map[string]string{"awslogs-group": "/ecs/app"})},
+			},
+		}},
+	})
+
+	targets, err := store.GetLogTargets(context.Background(), tdArn, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LogTarget{
+		{Group: "/ecs/app", Arn: "arn:aws:logs:eu-west-1:111111:log-group:/ecs/app", Region: "eu-west-1"},
+		{Group: "/ecs/app", Arn: "arn:aws:logs:us-east-1:111111:log-group:/ecs/app", Region: "us-east-1"},
+	}
+	if !slices.EqualFunc(targets, want, func(a, b LogTarget) bool {
+		return a.Group == b.Group && a.Arn == b.Arn && a.Region == b.Region
+	}) {
+		t.Errorf("Got targets %+v, Want: %+v", targets, want)
+	}
+}
@@ -5,11 +5,30 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/keidarcy/e1s/internal/utils"
 )
 
+// Upper bound of tasks listed on one page, the rest are not fetched
+const MaxTasks = 1000
+
+// Facts about a ListTasks result the UI tells users about
+type TasksInfo struct {
+	// Running list was empty and results are stopped tasks from a cluster-wide list
+	// (so the UI can warn). It is false when the user asked for stopped tasks directly.
+	StoppedFallback bool
+	// More than MaxTasks tasks matched and only the first MaxTasks were described
+	Capped bool
+}
+
+// Cached ListTasks result
+type taskList struct {
+	tasks []types.Task
+	info  TasksInfo
+}
+
 // Equivalent to
 // aws ecs list-tasks --cluster ${cluster} --service ${service}
 // OR
@@ -19,91 +38,132 @@ import (
 // aws ecs list-tasks --cluster ${cluster} --desired-status STOPPED
 // `aws ecs list-tasks --cluster ${CLUSTER} --service-name ${SERVICE} --desired-status STOPPED` return nothing
 // `aws ecs list-tasks --cluster ${CLUSTER} --desired-status STOPPED` return all stopped tasks in cluster
-// The bool is true only when the running list was empty and results are stopped tasks
-// from a cluster-wide list (so the UI can warn). It is false when the user asked for stopped tasks directly.
-func (store *Store) ListTasks(clusterName, serviceName *string, status types.DesiredStatus) ([]types.Task, bool, error) {
-	limit := int32(100)
+func (store *Store) ListTasks(ctx context.Context, clusterName, serviceName *string, status types.DesiredStatus) ([]types.Task, TasksInfo, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	key := aws.ToString(clusterName) + "/" + aws.ToString(serviceName) + "/" + string(status)
+	list, err := cached(ctx, store, cacheTasks, key, func(ctx context.Context) (taskList, error) {
+		return store.listTasks(ctx, clusterName, serviceName, status)
+	})
+	return list.tasks, list.info, err
+}
+
+func (store *Store) listTasks(ctx context.Context, clusterName, serviceName *string, status types.DesiredStatus) (taskList, error) {
+	info := TasksInfo{}
 	resultTasks := []types.Task{}
-	describeTasksInclude := []types.TaskField{
-		types.TaskFieldTags,
-	}
 	listTaskServiceName := serviceName
 
 	// When listing stopped tasks, ECS ignores service on ListTasks; we filter after DescribeTasks.
 	filterDescribedByService := status == types.DesiredStatusStopped
-	warnFallbackFromRunningToStopped := false
 
 	if status == types.DesiredStatusStopped {
 		listTaskServiceName = nil
 	}
 
-	listTasksOutput, err := store.ecs.ListTasks(context.Background(), &ecs.ListTasksInput{
+	taskARNs, capped, listErr := store.listTaskARNs(ctx, &ecs.ListTasksInput{
 		Cluster:       clusterName,
 		ServiceName:   listTaskServiceName,
 		DesiredStatus: status,
-		MaxResults:    &limit,
 	})
-
-	if err != nil {
-		slog.Warn("failed to run aws api to list tasks", "error", err)
-		return []types.Task{}, false, err
+	if listErr != nil && len(taskARNs) == 0 {
+		return taskList{info: info}, listErr
 	}
 
-	if status == types.DesiredStatusStopped && len(listTasksOutput.TaskArns) == 0 {
-		return nil, false, nil
+	if status == types.DesiredStatusStopped && len(taskARNs) == 0 {
+		return taskList{info: info}, nil
 	}
 
-	if status == types.DesiredStatusRunning && len(listTasksOutput.TaskArns) == 0 {
-		listTasksOutput, err = store.ecs.ListTasks(context.Background(), &ecs.ListTasksInput{
+	if status == types.DesiredStatusRunning && len(taskARNs) == 0 {
+		taskARNs, capped, listErr = store.listTaskARNs(ctx, &ecs.ListTasksInput{
 			Cluster:       clusterName,
 			DesiredStatus: types.DesiredStatusStopped,
-			MaxResults:    &limit,
 		})
-		if err != nil {
-			slog.Warn("failed to run aws api to list tasks", "error", err)
-			return nil, false, err
+		if listErr != nil && len(taskARNs) == 0 {
+			return taskList{info: info}, listErr
 		}
-		if len(listTasksOutput.TaskArns) == 0 {
-			return nil, false, nil
+		if len(taskARNs) == 0 {
+			return taskList{info: info}, nil
 		}
 		filterDescribedByService = true
-		warnFallbackFromRunningToStopped = true
+		info.StoppedFallback = true
 	}
+	info.Capped = capped
 
-	describeTasksOutput, err := store.ecs.DescribeTasks(context.Background(), &ecs.DescribeTasksInput{
-		Cluster: clusterName,
-		Tasks:   listTasksOutput.TaskArns,
-		Include: describeTasksInclude,
-	})
-
+	describedTasks, err := store.describeTasks(ctx, clusterName, taskARNs)
 	if err != nil {
-		slog.Warn("failed to run aws api to describe tasks", "error", err)
-		return []types.Task{}, false, err
+		return taskList{info: info}, err
 	}
 
-	if len(describeTasksOutput.Tasks) > 0 {
-		if !filterDescribedByService {
-			resultTasks = append(resultTasks, describeTasksOutput.Tasks...)
-		} else {
-			for _, t := range describeTasksOutput.Tasks {
-				if serviceName != nil {
-					if *serviceName == utils.GetServiceByTaskGroup(t.Group) {
-						resultTasks = append(resultTasks, t)
-					}
-				} else {
+	if !filterDescribedByService {
+		resultTasks = append(resultTasks, describedTasks...)
+	} else {
+		for _, t := range describedTasks {
+			if serviceName != nil {
+				if *serviceName == utils.GetServiceByTaskGroup(t.Group) {
 					resultTasks = append(resultTasks, t)
 				}
+			} else {
+				resultTasks = append(resultTasks, t)
 			}
 		}
 	}
 
-	return resultTasks, warnFallbackFromRunningToStopped, nil
+	// Partial list error, if any, tells the UI not all tasks are shown
+	return taskList{tasks: resultTasks, info: info}, listErr
+}
+
+// Follow NextToken until all task ARNs are listed or MaxTasks is reached
+// The bool is true when listing stopped at MaxTasks with more pages left
+func (store *Store) listTaskARNs(ctx context.Context, params *ecs.ListTasksInput) ([]string, bool, error) {
+	limit := int32(100)
+	params.MaxResults = &limit
+	capped := false
+	listed := 0
+
+	taskARNs, err := paginate(ctx, func(ctx context.Context, token *string) ([]string, *string, error) {
+		params.NextToken = token
+		listTasksOutput, err := store.ecs.ListTasks(ctx, params)
+		if err != nil {
+			slog.Warn("failed to run aws api to list tasks", "error", err)
+			return nil, nil, err
+		}
+		listed += len(listTasksOutput.TaskArns)
+		if listed > MaxTasks || (listed == MaxTasks && listTasksOutput.NextToken != nil) {
+			capped = true
+			return listTasksOutput.TaskArns, nil, nil
+		}
+		return listTasksOutput.TaskArns, listTasksOutput.NextToken, nil
+	})
+	if len(taskARNs) > MaxTasks {
+		taskARNs = taskARNs[:MaxTasks]
+	}
+	return taskARNs, capped, err
+}
+
+// Describe tasks in batches of 100, keeps the order of taskARNs
+func (store *Store) describeTasks(ctx context.Context, clusterName *string, taskARNs []string) ([]types.Task, error) {
+	return fetchBatches(ctx, taskARNs, 100, func(ctx context.Context, tasks []string) ([]types.Task, error) {
+		describeTasksOutput, err := store.ecs.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+			Cluster: clusterName,
+			Tasks:   tasks,
+			Include: []types.TaskField{types.TaskFieldTags},
+		})
+		if err != nil {
+			slog.Warn("failed to run aws api to describe tasks", "error", err)
+			return nil, err
+		}
+		return describeTasksOutput.Tasks, nil
+	})
 }
 
 // aws ecs register-task-definition --family ${{family}} --...
 // return registered task definition revision
-func (store *Store) RegisterTaskDefinition(input *ecs.RegisterTaskDefinitionInput) (string, int32, error) {
-	registeredTdOutput, err := store.ecs.RegisterTaskDefinition(context.Background(), input)
+func (store *Store) RegisterTaskDefinition(ctx context.Context, input *ecs.RegisterTaskDefinitionInput) (string, int32, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	registeredTdOutput, err := store.ecs.RegisterTaskDefinition(ctx, input)
 	if err != nil {
 		return "", 0, err
 	}
@@ -113,17 +173,24 @@ func (store *Store) RegisterTaskDefinition(input *ecs.RegisterTaskDefinitionInpu
 }
 
 // aws ecs stop-task --cluster ${cluster} --task ${taskId}
-func (store *Store) StopTask(input *ecs.StopTaskInput) error {
-	_, err := store.ecs.StopTask(context.Background(), input)
+func (store *Store) StopTask(ctx context.Context, input *ecs.StopTaskInput) error {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	_, err := store.ecs.StopTask(ctx, input)
 	if err != nil {
 		return err
 	}
+	store.cache.invalidate()
 	return nil
 }
 
 // aws ecs describe-container-instances --cluster ${cluster} --container-instances ${instanceId}
-func (store *Store) GetTaskInstanceId(cluster, containerInstance *string) (string, error) {
-	describeOutput, err := store.ecs.DescribeContainerInstances(context.Background(), &ecs.DescribeContainerInstancesInput{
+func (store *Store) GetTaskInstanceId(ctx context.Context, cluster, containerInstance *string) (string, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	describeOutput, err := store.ecs.DescribeContainerInstances(ctx, &ecs.DescribeContainerInstancesInput{
 		Cluster:            cluster,
 		ContainerInstances: []string{*containerInstance},
 	})
@@ -9,7 +9,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/keidarcy/e1s/internal/utils"
-	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -19,12 +18,20 @@ const (
 
 // Equivalent to
 // aws ecs describe-task-definition --task-definition ${taskDefinition}
-func (store *Store) DescribeTaskDefinition(tdArn *string) (types.TaskDefinition, error) {
+func (store *Store) DescribeTaskDefinition(ctx context.Context, tdArn *string) (types.TaskDefinition, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
 
+	return cached(ctx, store, cacheTaskDefinitions, aws.ToString(tdArn), func(ctx context.Context) (types.TaskDefinition, error) {
+		return store.describeTaskDefinition(ctx, tdArn)
+	})
+}
+
+func (store *Store) describeTaskDefinition(ctx context.Context, tdArn *string) (types.TaskDefinition, error) {
 	include := []types.TaskDefinitionField{
 		types.TaskDefinitionFieldTags,
 	}
-	taskDefinition, err := store.ecs.DescribeTaskDefinition(context.Background(), &ecs.DescribeTaskDefinitionInput{
+	taskDefinition, err := store.ecs.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
 		TaskDefinition: tdArn,
 		Include:        include,
 	})
@@ -40,8 +47,11 @@ type TaskDefinitionRevision = []string
 
 // Equivalent to
 // aws ecs list-task-definitions --family-prefix ${prefix}
-func (store *Store) ListTaskDefinition(familyName *string) (TaskDefinitionRevision, error) {
-	listTaskDefinitions, err := store.ecs.ListTaskDefinitions(context.Background(), &ecs.ListTaskDefinitionsInput{
+func (store *Store) ListTaskDefinition(ctx context.Context, familyName *string) (TaskDefinitionRevision, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	listTaskDefinitions, err := store.ecs.ListTaskDefinitions(ctx, &ecs.ListTaskDefinitionsInput{
 		FamilyPrefix: familyName,
 		MaxResults:   aws.Int32(MaxTaskDefinitionRevision),
 		Sort:         types.SortOrderDesc,
@@ -58,41 +68,41 @@ func (store *Store) ListTaskDefinition(familyName *string) (TaskDefinitionRevisi
 // Equivalent to
 // aws ecs list-task-definitions --family-prefix ${prefix}
 // aws ecs describe-task-definition --task-definition ${taskDefinition}
-func (store *Store) ListFullTaskDefinition(taskDefinition *string) ([]types.TaskDefinition, error) {
+func (store *Store) ListFullTaskDefinition(ctx context.Context, taskDefinition *string) ([]types.TaskDefinition, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
 	td := strings.Split(utils.ArnToName(taskDefinition), ":")
 	familyName := td[0]
-	list, err := store.ListTaskDefinition(&familyName)
+	list, err := store.ListTaskDefinition(ctx, &familyName)
 
 	if err != nil {
 		slog.Warn("failed to run aws api to run list task definition in ListFullTaskDefinition", "error", err)
 		return []types.TaskDefinition{}, err
 	}
 
-	results := []types.TaskDefinition{}
-	g := new(errgroup.Group)
-
-	for _, t := range list {
-		t := t
-		g.Go(func() error {
-			d, err := store.DescribeTaskDefinition(&t)
-			if err != nil {
-				slog.Warn("failed to run aws api to describe task definition", "error", err)
-				return err
-			}
-			results = append(results, d)
-			return nil
-		})
+	// DescribeTaskDefinition takes one task definition per call
+	results, err := fetchBatches(ctx, list, 1, func(ctx context.Context, tds []string) ([]types.TaskDefinition, error) {
+		d, err := store.DescribeTaskDefinition(ctx, &tds[0])
+		if err != nil {
+			return nil, err
+		}
+		return []types.TaskDefinition{d}, nil
+	})
+	if err != nil {
+		return []types.TaskDefinition{}, err
 	}
 
-	err = g.Wait()
-
-	return results, err
+	return results, nil
 }
 
 // Equivalent to
 // aws ecs list-task-definition-families --family-prefix ${prefix}
-func (store *Store) ListTaskDefinitionFamilies(familyPrefix *string) ([]string, error) {
-	familiesOutput, err := store.ecs.ListTaskDefinitionFamilies(context.Background(), &ecs.ListTaskDefinitionFamiliesInput{
+func (store *Store) ListTaskDefinitionFamilies(ctx context.Context, familyPrefix *string) ([]string, error) {
+	ctx, cancel := store.withTimeout(ctx)
+	defer cancel()
+
+	familiesOutput, err := store.ecs.ListTaskDefinitionFamilies(ctx, &ecs.ListTaskDefinitionFamiliesInput{
 		FamilyPrefix: familyPrefix,
 		MaxResults:   aws.Int32(MaxTaskDefinitionFamily),
 		Status:       types.TaskDefinitionFamilyStatusActive,
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Fixture store of cluster big with count running tasks of service big
+func newTasksStore(count int) (*Store, *Fixtures) {
+	fixtures := &Fixtures{Region: "us-east-1"}
+	for i := range count {
+		fixtures.Tasks = append(fixtures.Tasks, types.Task{
+			TaskArn:       aws.String(fmt.Sprintf("arn:aws:ecs:us-east-1:111111:task/big/%04d", i)),
+			ClusterArn:    aws.String("arn:aws:ecs:us-east-1:111111:cluster/big"),
+			Group:         aws.String("service:big"),
+			DesiredStatus: aws.String(string(types.DesiredStatusRunning)),
+		})
+	}
+	return NewFixtureStore(fixtures), fixtures
+}
+
+func TestListTaskARNs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		count  int
+		want   int
+		capped bool
+	}{
+		{name: "several pages", count: 250, want: 250},
+		{name: "exactly max tasks", count: MaxTasks, want: MaxTasks},
+		{name: "more than max tasks", count: MaxTasks + 1, want: MaxTasks, capped: true},
+		{name: "stops paging at max tasks", count: 2 * MaxTasks, want: MaxTasks, capped: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, fixtures := newTasksStore(tc.count)
+			arns, capped, err := store.listTaskARNs(context.Background(), &ecs.ListTasksInput{
+				Cluster:       aws.String("big"),
+				DesiredStatus: types.DesiredStatusRunning,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(arns) != tc.want || capped != tc.capped {
+				t.Errorf("Got %d tasks capped %t, Want: %d tasks capped %t", len(arns), capped, tc.want, tc.capped)
+			}
+			if last := *fixtures.Tasks[tc.want-1].TaskArn; arns[len(arns)-1] != last {
+				t.Errorf("Got last task %s, Want: %s", arns[len(arns)-1], last)
+			}
+		})
+	}
+}
+
+func TestListTasksPagination(t *testing.T) {
+	store, fixtures := newTasksStore(MaxTasks + 50)
+	tasks, info, err := store.ListTasks(context.Background(), aws.String("big"), aws.String("big"), types.DesiredStatusRunning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != MaxTasks || !info.Capped {
+		t.Errorf("Got %d tasks capped %t, Want: %d tasks capped", len(tasks), info.Capped, MaxTasks)
+	}
+	// described in batches, kept in listed order
+	for i, task := range tasks {
+		if *task.TaskArn != *fixtures.Tasks[i].TaskArn {
+			t.Fatalf("Got task %s at %d, Want: %s", *task.TaskArn, i, *fixtures.Tasks[i].TaskArn)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package color
 import "fmt"
 
 var (
-	GreenFmt = ""
-	GrayFmt  = ""
+	GreenFmt  = ""
+	GrayFmt   = ""
+	YellowFmt = ""
+	RedFmt    = ""
 
 	FooterSelectedItemFmt = ""
 	FooterItemFmt         = ""
@@ -19,18 +21,31 @@ var (
 	HelpKeyFmt         = ""
 	HelpDescriptionFmt = ""
 
-	NoticeInfoFmt  = ""
-	NoticeWarnFmt  = ""
-	NoticeErrorFmt = ""
+	NoticeInfoFmt    = ""
+	NoticeWarnFmt    = ""
+	NoticeErrorFmt   = ""
+	NoticeLoadingFmt = ""
+
+	RefreshStatusFmt = ""
+
+	ContextItemFmt     = ""
+	ContextExpiringFmt = ""
 
 	TableTitleFmt          = ""
 	TableSecondaryTitleFmt = ""
 	TableClusterTasksFmt   = ""
+	TableCappedFmt         = ""
+
+	MetricsLabelFmt          = ""
+	MetricsWindowFmt         = ""
+	MetricsWindowSelectedFmt = ""
 )
 
 func (c Colors) initFmt() {
 	GreenFmt = fmt.Sprintf("[%s]%%s[-:-:-]", c.Green)
 	GrayFmt = fmt.Sprintf("[%s]%%s[-:-:-]", c.Gray)
+	YellowFmt = fmt.Sprintf("[%s]%%s[-:-:-]", c.Yellow)
+	RedFmt = fmt.Sprintf("[%s]%%s[-:-:-]", c.Red)
 
 	FooterSelectedItemFmt = fmt.Sprintf("[%s:%s:b] <%%s> [-:-:-]", c.Black, c.Cyan)
 	FooterItemFmt = fmt.Sprintf("[%s:%s:] <%%s> [-:-:-]", c.Black, c.Gray)
@@ -48,8 +63,19 @@ func (c Colors) initFmt() {
 	NoticeInfoFmt = fmt.Sprintf("✅ [%s::]%%s[-:-:-]", c.Green)
 	NoticeWarnFmt = fmt.Sprintf("😔 [%s::]%%s[-:-:-]", c.Yellow)
 	NoticeErrorFmt = fmt.Sprintf("💥 [%s::]%%s[-:-:-]", c.Red)
+	NoticeLoadingFmt = fmt.Sprintf("⏳ [%s::]%%s[-:-:-]", c.Gray)
+
+	RefreshStatusFmt = fmt.Sprintf("[%s::]%%s[-:-:-] ", c.Gray)
+
+	ContextItemFmt = fmt.Sprintf(" [%s::]%%s:[%s::b] %%s[-:-:-] ", c.Gray, c.Cyan)
+	ContextExpiringFmt = fmt.Sprintf(" [%s::]%%s:[%s::b] %%s[-:-:-] ", c.Gray, c.Red)
 
 	TableTitleFmt = fmt.Sprintf(" [%s::-]<[%s::b]%%s[%s::-]>[%s::b]%%s[%s::-]([%s::b]%%d[%s::-]) ", c.Cyan, c.Magenta, c.Cyan, c.Cyan, c.Cyan, c.Magenta, c.Cyan)
 	TableSecondaryTitleFmt = fmt.Sprintf(" [%s]%%s([%s::b]%%s[%s:-:-])[%s::-][[%s::-]%%s[-:-:-]] ", c.Blue, c.Magenta, c.Blue, c.FgColor, c.Green)
 	TableClusterTasksFmt = fmt.Sprintf("[%s]%%d Pending[-] | [%s]%%d Running", c.Blue, c.Green)
+	TableCappedFmt = fmt.Sprintf(" [%s:%s]<%%s>[-:-] ", c.Black, c.Yellow)
+
+	MetricsLabelFmt = fmt.Sprintf("[%s::b]%%-16s[-:-:-]", c.Cyan)
+	MetricsWindowFmt = fmt.Sprintf("[%s::] %%s [-:-:-]", c.Gray)
+	MetricsWindowSelectedFmt = fmt.Sprintf("[%s:%s:b] %%s [-:-:-]", c.Black, c.Cyan)
 }
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/rivo/tview"
+)
+
+// Credentials expiring sooner are highlighted
+const expiringSoon = 5 * time.Minute
+
+// Who and where e1s is acting as
+type ContextInfo struct {
+	Account      string
+	AccountAlias string
+	Principal    string
+	Session      string
+	Profile      string
+	Region       string
+	// Zero when credentials don't expire
+	Expires time.Time
+}
+
+// Context line above resource pages, counts down credentials expiry
+type ContextBar struct {
+	*tview.TextView
+	dispatcher *Dispatcher
+
+	mu   sync.Mutex
+	info ContextInfo
+	// Text on screen, redraw only when it changes
+	text string
+}
+
+func NewContextBar(dispatcher *Dispatcher, theme color.Colors) *ContextBar {
+	t := tview.NewTextView().
+		SetDynamicColors(true)
+	t.SetBackgroundColor(color.Color(theme.BgColor))
+	return &ContextBar{
+		TextView:   t,
+		dispatcher: dispatcher,
+	}
+}
+
+// Keep expiry countdown counting until ctx is done, call once the app is about to run
+func (c *ContextBar) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if text, changed := c.render(); changed {
+					c.dispatcher.Dispatch(func() {
+						c.SetText(text)
+					})
+				}
+			}
+		}
+	}()
+}
+
+// Set shows info, must be called on the main loop
+func (c *ContextBar) Set(info ContextInfo) {
+	c.mu.Lock()
+	c.info = info
+	c.mu.Unlock()
+	if text, changed := c.render(); changed {
+		c.SetText(text)
+	}
+}
+
+func (c *ContextBar) render() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.info
+
+	b := strings.Builder{}
+	item := func(name, value string) {
+		if value != "" {
+			b.WriteString(fmt.Sprintf(color.ContextItemFmt, name, value))
+		}
+	}
+	account := i.Account
+	if i.AccountAlias != "" {
+		account = fmt.Sprintf("%s (%s)", i.Account, i.AccountAlias)
+	}
+	item("account", account)
+	principal := i.Principal
+	if i.Session != "" {
+		principal = i.Principal + "/" + i.Session
+	}
+	item("role", principal)
+	item("profile", i.Profile)
+	item("region", i.Region)
+	if !i.Expires.IsZero() {
+		left := time.Until(i.Expires).Truncate(time.Second)
+		switch {
+		case left <= 0:
+			b.WriteString(fmt.Sprintf(color.ContextExpiringFmt, "credentials", "expired"))
+		case left < expiringSoon:
+			b.WriteString(fmt.Sprintf(color.ContextExpiringFmt, "expires in", left))
+		default:
+			item("expires in", left.String())
+		}
+	}
+
+	text := b.String()
+	changed := text != c.text
+	c.text = text
+	return text, changed
+}
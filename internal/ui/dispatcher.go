@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"sync"
+
+	"github.com/rivo/tview"
+)
+
+// Dispatcher runs UI updates on the tview main loop.
+// tview primitives are not thread-safe, goroutines must change them through Dispatch.
+type Dispatcher struct {
+	app *tview.Application
+
+	mu    sync.Mutex
+	queue []func()
+	wake  chan struct{}
+}
+
+func NewDispatcher(app *tview.Application) *Dispatcher {
+	d := &Dispatcher{
+		app:  app,
+		wake: make(chan struct{}, 1),
+	}
+	go d.pump()
+	return d
+}
+
+// Dispatch queues f to run on the main loop followed by a redraw.
+// It never blocks, so it is safe on the main loop too, and keeps the order of calls.
+func (d *Dispatcher) Dispatch(f func()) {
+	d.mu.Lock()
+	d.queue = append(d.queue, f)
+	d.mu.Unlock()
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Hand queued updates over to tview, blocks while tview's own queue is full
+func (d *Dispatcher) pump() {
+	for range d.wake {
+		d.mu.Lock()
+		queue := d.queue
+		d.queue = nil
+		d.mu.Unlock()
+		for _, f := range queue {
+			d.app.QueueUpdateDraw(f)
+		}
+	}
+}
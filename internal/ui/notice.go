@@ -11,50 +11,44 @@ import (
 
 type Notice struct {
 	*tview.TextView
-	app        *tview.Application
-	timer      *time.Timer
-	forceTimer *time.Timer
+	dispatcher *Dispatcher
+	// Bumped on every message so a pending clear leaves newer messages alone, main loop only
+	seq int
 }
 
-func NewNotice(app *tview.Application, theme color.Colors) *Notice {
+func NewNotice(dispatcher *Dispatcher, theme color.Colors) *Notice {
 	t := tview.NewTextView().
 		SetTextAlign(tview.AlignCenter).
 		SetDynamicColors(true)
 	t.SetBackgroundColor(color.Color(theme.BgColor))
 	return &Notice{
 		TextView:   t,
-		app:        app,
-		timer:      time.NewTimer(time.Second * 3),
-		forceTimer: time.NewTimer(time.Second * 8),
+		dispatcher: dispatcher,
 	}
 }
 
+// Show message for 3 seconds, safe to call from any goroutine
 func (n *Notice) sendMessage(s string) {
-	update := func() {
+	n.dispatcher.Dispatch(func() {
+		n.seq++
+		seq := n.seq
 		n.SetText(s)
-	}
-	go n.app.QueueUpdateDraw(update)
-	n.clear()
-	n.forceClear()
-}
-
-func (n *Notice) clear() {
-	n.timer.Reset(time.Second * 3)
-	go func() {
-		<-n.timer.C
-		n.app.QueueUpdate(func() {
-			n.Clear()
+		time.AfterFunc(time.Second*3, func() {
+			n.dispatcher.Dispatch(func() {
+				if n.seq == seq {
+					n.Clear()
+				}
+			})
 		})
-	}()
+	})
 }
 
-func (n *Notice) forceClear() {
-	n.forceTimer.Reset(time.Second * 8)
-	go func() {
-		<-n.forceTimer.C
-		n.Clear()
-		n.app.Draw()
-	}()
+// Loading keeps the message until the next notice, must be called on the main loop
+func (n *Notice) Loading(s string) {
+	m := fmt.Sprintf(color.NoticeLoadingFmt, s)
+	slog.Debug("notice loading", "msg", m)
+	n.seq++
+	n.SetText(m)
 }
 
 func (n *Notice) Info(s string) {
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/rivo/tview"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Footer indicator of page loads, a spinner while loading and the age of the data afterwards
+type RefreshStatus struct {
+	*tview.TextView
+	dispatcher *Dispatcher
+
+	mu          sync.Mutex
+	loading     bool
+	refreshedAt time.Time
+	// Text on screen, redraw only when it changes
+	text string
+}
+
+func NewRefreshStatus(dispatcher *Dispatcher, theme color.Colors) *RefreshStatus {
+	t := tview.NewTextView().
+		SetTextAlign(tview.AlignRight).
+		SetDynamicColors(true)
+	t.SetBackgroundColor(color.Color(theme.BgColor))
+	return &RefreshStatus{
+		TextView:   t,
+		dispatcher: dispatcher,
+	}
+}
+
+// Keep spinner spinning and age counting until ctx is done, call once the app is about to run
+func (r *RefreshStatus) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if text, changed := r.render(); changed {
+					r.dispatcher.Dispatch(func() {
+						r.SetText(text)
+					})
+				}
+			}
+		}
+	}()
+}
+
+// Start shows the spinner, must be called on the main loop
+func (r *RefreshStatus) Start() {
+	r.mu.Lock()
+	r.loading = true
+	r.mu.Unlock()
+	r.show()
+}
+
+// Done stops the spinner, refreshed resets the age. Must be called on the main loop
+func (r *RefreshStatus) Done(refreshed bool) {
+	r.mu.Lock()
+	r.loading = false
+	if refreshed {
+		r.refreshedAt = time.Now()
+	}
+	r.mu.Unlock()
+	r.show()
+}
+
+func (r *RefreshStatus) show() {
+	if text, changed := r.render(); changed {
+		r.SetText(text)
+	}
+}
+
+func (r *RefreshStatus) render() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	text := ""
+	switch {
+	case r.loading:
+		frame := spinnerFrames[time.Now().UnixMilli()/100%int64(len(spinnerFrames))]
+		text = fmt.Sprintf(color.RefreshStatusFmt, frame+" refreshing")
+	case !r.refreshedAt.IsZero():
+		text = fmt.Sprintf(color.RefreshStatusFmt, fmt.Sprintf("refreshed %s ago", time.Since(r.refreshedAt).Truncate(time.Second)))
+	}
+	changed := text != r.text
+	r.text = text
+	return text, changed
+}
@@ -6,28 +6,56 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/keidarcy/e1s/internal/color"
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	EmptyText               = "<empty>"
-	clusterFmt              = "https://%s.console.aws.amazon.com/ecs/v2/clusters/%s"
+	clusterFmt              = "https://%s.%s/ecs/v2/clusters/%s"
 	regionFmt               = "?region=%s"
 	serviceFmt              = "/services/%s"
 	taskFmt                 = "/tasks/%s"
 	clusterURLFmt           = clusterFmt + regionFmt
 	serviceURLFmt           = clusterFmt + serviceFmt + regionFmt
 	taskURLFmt              = clusterFmt + serviceFmt + taskFmt + regionFmt
-	taskDefinitionURLFmt    = "https://%s.console.aws.amazon.com/ecs/v2/task-definitions/%s/%s/containers?region=%s"
-	serviceDeploymentURLFmt = "https://%s.console.aws.amazon.com/ecs/v2/clusters/%s/services/%s/service-deployments/%s?region=%s"
+	taskDefinitionURLFmt    = "https://%s.%s/ecs/v2/task-definitions/%s/%s/containers?region=%s"
+	serviceDeploymentURLFmt = "https://%s.%s/ecs/v2/clusters/%s/services/%s/service-deployments/%s?region=%s"
 )
 
+type partitionDomains struct {
+	console   string
+	dnsSuffix string
+}
+
+// Console domain and DNS suffix of AWS endpoints by partition
+var partitions = map[string]partitionDomains{
+	"aws":        {console: "console.aws.amazon.com", dnsSuffix: "amazonaws.com"},
+	"aws-cn":     {console: "console.amazonaws.cn", dnsSuffix: "amazonaws.com.cn"},
+	"aws-us-gov": {console: "console.amazonaws-us-gov.com", dnsSuffix: "amazonaws.com"},
+}
+
+// Domains of partition, the aws ones for an unknown partition
+func domainsOf(partition string) partitionDomains {
+	if d, ok := partitions[partition]; ok {
+		return d
+	}
+	return partitions["aws"]
+}
+
+// DNS suffix of AWS endpoints in partition, e.g. amazonaws.com.cn in aws-cn
+func PartitionDNSSuffix(partition string) string {
+	return domainsOf(partition).dnsSuffix
+}
+
 func ArnToName(arn *string) string {
 	if arn == nil {
 		return EmptyText
@@ -44,6 +72,42 @@ func ArnToFullName(arn *string) string {
 	return ss[len(ss)-1]
 }
 
+// Region part of an ARN, empty when arn is not an ARN
+func ArnToRegion(arn *string) string {
+	if arn == nil {
+		return ""
+	}
+	ss := strings.Split(*arn, ":")
+	if len(ss) < 6 || ss[0] != "arn" {
+		return ""
+	}
+	return ss[3]
+}
+
+// Partition part of an ARN, aws when arn is not an ARN
+func ArnToPartition(arn *string) string {
+	if arn == nil {
+		return "aws"
+	}
+	ss := strings.Split(*arn, ":")
+	if len(ss) < 6 || ss[0] != "arn" || ss[1] == "" {
+		return "aws"
+	}
+	return ss[1]
+}
+
+// Account ID part of an ARN, empty when arn is not an ARN
+func ArnToAccount(arn *string) string {
+	if arn == nil {
+		return ""
+	}
+	ss := strings.Split(*arn, ":")
+	if len(ss) < 6 || ss[0] != "arn" {
+		return ""
+	}
+	return ss[4]
+}
+
 func ShowString(s *string) string {
 	if s == nil {
 		return EmptyText
@@ -107,6 +171,7 @@ func ArnToUrl(arn string, taskService string) string {
 	}
 
 	region := components[3]
+	domain := domainsOf(ArnToPartition(&arn)).console
 	clusterName := ""
 	serviceName := ""
 	taskName := ""
@@ -114,29 +179,44 @@ func ArnToUrl(arn string, taskService string) string {
 	switch names[0] {
 	case "cluster":
 		clusterName = names[1]
-		return fmt.Sprintf(clusterURLFmt, region, clusterName, region)
+		return fmt.Sprintf(clusterURLFmt, region, domain, clusterName, region)
 	case "service":
 		clusterName = names[1]
 		serviceName = names[2]
-		return fmt.Sprintf(serviceURLFmt, region, clusterName, serviceName, region)
+		return fmt.Sprintf(serviceURLFmt, region, domain, clusterName, serviceName, region)
 	case "service-deployment":
 		clusterName = names[1]
 		serviceName = names[2]
 		deploymentId := names[3]
-		return fmt.Sprintf(serviceDeploymentURLFmt, region, clusterName, serviceName, deploymentId, region)
+		return fmt.Sprintf(serviceDeploymentURLFmt, region, domain, clusterName, serviceName, deploymentId, region)
 	case "task", "container":
 		clusterName = names[1]
 		taskName = names[2]
-		return fmt.Sprintf(taskURLFmt, region, clusterName, taskService, taskName, region)
+		return fmt.Sprintf(taskURLFmt, region, domain, clusterName, taskService, taskName, region)
 	case "task-definition":
 		taskDefName := names[1]
 		revision := names[2]
-		return fmt.Sprintf(taskDefinitionURLFmt, region, taskDefName, revision, region)
+		return fmt.Sprintf(taskDefinitionURLFmt, region, domain, taskDefName, revision, region)
 	default:
 		return ""
 	}
 }
 
+// Wrap console URL in a sign-in URL template, e.g. an AWS SSO portal link.
+// {url} is replaced with the escaped console URL, {account}, {region} and {partition}
+// with the ones of arn. The console URL is returned as is without template.
+func ConsoleSignInUrl(template string, consoleUrl string, arn string) string {
+	if template == "" || consoleUrl == "" {
+		return consoleUrl
+	}
+	return strings.NewReplacer(
+		"{url}", url.QueryEscape(consoleUrl),
+		"{account}", ArnToAccount(&arn),
+		"{region}", ArnToRegion(&arn),
+		"{partition}", ArnToPartition(&arn),
+	).Replace(template)
+}
+
 func OpenURL(url string) error {
 	var err error
 
@@ -157,22 +237,33 @@ func OpenURL(url string) error {
 	return nil
 }
 
-func BuildMeterText(f float64) string {
-	const yesBlock = "█"
-	const noBlock = "▒"
-	i := int(f)
-
-	yesNum := i / 5
-	if yesNum == 0 {
-		yesNum++
+// Build a sparkline of width characters from values scaled between 0 and top,
+// values are bucketed by their peak when there are more than width of them.
+// top <= 0 scales to the largest value.
+func Sparkline(values []float64, top float64, width int) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	if len(values) == 0 || width <= 0 {
+		return ""
 	}
-	noNum := 20 - yesNum
-	meterVal := strings.Join([]string{
-		strings.Repeat(yesBlock, yesNum),
-		strings.Repeat(noBlock, noNum),
-	}, "")
-
-	return meterVal + " " + fmt.Sprintf("%.2f", f) + "%"
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i := range buckets {
+			buckets[i] = slices.Max(values[i*len(values)/width : (i+1)*len(values)/width])
+		}
+		values = buckets
+	}
+	if top <= 0 {
+		top = slices.Max(values)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if top > 0 {
+			level = max(0, min(len(levels)-1, int(v/top*float64(len(levels)-1))))
+		}
+		b.WriteRune(levels[level])
+	}
+	return b.String()
 }
 
 func ShowVersion() string {
@@ -381,3 +472,13 @@ func Duration(start, end time.Time) string {
 	}
 	return fmt.Sprintf("%ds", int(duration.Seconds()))
 }
+
+// YAMLToJSON decodes YAML generically and encodes it as JSON, so types without yaml tags
+// can be unmarshalled from YAML with their JSON field names
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
@@ -26,13 +26,14 @@ func TestArnToURL(t *testing.T) {
 	taskDef1 := "my-task-def"
 	revision1 := "1"
 	arn1 := fmt.Sprintf(clusterArnFmt, testRegion, cluster1)
-	url1 := fmt.Sprintf(clusterURLFmt, testRegion, cluster1, testRegion)
+	domain := "console.aws.amazon.com"
+	url1 := fmt.Sprintf(clusterURLFmt, testRegion, domain, cluster1, testRegion)
 	arn2 := fmt.Sprintf(serviceArnFmt, testRegion, cluster1, service1)
-	url2 := fmt.Sprintf(serviceURLFmt, testRegion, cluster1, service1, testRegion)
+	url2 := fmt.Sprintf(serviceURLFmt, testRegion, domain, cluster1, service1, testRegion)
 	arn3 := fmt.Sprintf(taskArnFmt, testRegion, cluster1, task1)
-	url3 := fmt.Sprintf(taskURLFmt, testRegion, cluster1, taskService1, task1, testRegion)
+	url3 := fmt.Sprintf(taskURLFmt, testRegion, domain, cluster1, taskService1, task1, testRegion)
 	arn4 := fmt.Sprintf(taskDefinitionArnFmt, testRegion, taskDef1, revision1)
-	url4 := fmt.Sprintf(taskDefinitionURLFmt, testRegion, taskDef1, revision1, testRegion)
+	url4 := fmt.Sprintf(taskDefinitionURLFmt, testRegion, domain, taskDef1, revision1, testRegion)
 
 	testCases := []struct {
 		name string
@@ -71,6 +72,20 @@ func TestArnToURL(t *testing.T) {
 			},
 			want: url4,
 		},
+		{
+			name: "china cluster arn convert",
+			args: Args{
+				arn: "arn:aws-cn:ecs:cn-north-1:111111:cluster/cluster1",
+			},
+			want: "https://cn-north-1.console.amazonaws.cn/ecs/v2/clusters/cluster1?region=cn-north-1",
+		},
+		{
+			name: "govcloud service arn convert",
+			args: Args{
+				arn: "arn:aws-us-gov:ecs:us-gov-west-1:111111:service/cluster1/service1",
+			},
+			want: "https://us-gov-west-1.console.amazonaws-us-gov.com/ecs/v2/clusters/cluster1/services/service1?region=us-gov-west-1",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -83,38 +98,64 @@ func TestArnToURL(t *testing.T) {
 	}
 }
 
-func TextBuildMeterText(t *testing.T) {
-	testCases := []struct {
-		name  string
-		input float64
-		want  string
+func TestArnToRegion(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want string
 	}{
-		{
-			name:  "value=2.123",
-			input: 2.123,
-			want:  "█▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒",
-		},
-		{
-			name:  "value=12.123",
-			input: 12.123,
-			want:  "██▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒",
-		},
-		{
-			name:  "value=2.123",
-			input: 52.123,
-			want:  "██████████▒▒▒▒▒▒▒▒▒▒",
-		},
+		{fmt.Sprintf(clusterArnFmt, "eu-west-1", "cluster1"), "eu-west-1"},
+		{fmt.Sprintf(taskDefinitionArnFmt, testRegion, "my-task-def", "1"), testRegion},
+		{"cluster1", ""},
 	}
+	for _, tt := range tests {
+		if got := ArnToRegion(&tt.arn); got != tt.want {
+			t.Errorf("ArnToRegion(%q) = %q, want %q", tt.arn, got, tt.want)
+		}
+	}
+}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := BuildMeterText(tc.input)
-			if result != tc.want {
-				t.Errorf("input: %v, want: %v, results %v\n", tc.input, tc.want, result)
-			}
-		})
+func TestPartitionDNSSuffix(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want string
+	}{
+		{"arn:aws-cn:ecs:cn-north-1:111111:task/cluster1/task1", "amazonaws.com.cn"},
+		{"arn:aws-us-gov:ecs:us-gov-west-1:111111:task/cluster1/task1", "amazonaws.com"},
+		{"cluster1", "amazonaws.com"},
+	}
+	for _, tt := range tests {
+		if got := PartitionDNSSuffix(ArnToPartition(&tt.arn)); got != tt.want {
+			t.Errorf("PartitionDNSSuffix(%q) = %q, want %q", tt.arn, got, tt.want)
+		}
+	}
+}
+
+func TestConsoleSignInUrl(t *testing.T) {
+	arn := "arn:aws:ecs:us-east-1:123456789012:cluster/cluster1"
+	consoleUrl := ArnToUrl(arn, "")
+	if got := ConsoleSignInUrl("", consoleUrl, arn); got != consoleUrl {
+		t.Errorf("Got: %s, Want: %s", got, consoleUrl)
 	}
+	template := "https://my.awsapps.com/start/#/console?account_id={account}&role_name=Admin&destination={url}"
+	want := "https://my.awsapps.com/start/#/console?account_id=123456789012&role_name=Admin&destination=https%3A%2F%2Fus-east-1.console.aws.amazon.com%2Fecs%2Fv2%2Fclusters%2Fcluster1%3Fregion%3Dus-east-1"
+	if got := ConsoleSignInUrl(template, consoleUrl, arn); got != want {
+		t.Errorf("Got: %s, Want: %s", got, want)
+	}
+}
 
+func TestArnToAccount(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want string
+	}{
+		{"arn:aws:ecs:us-east-1:123456789012:cluster/cluster1", "123456789012"},
+		{"cluster1", ""},
+	}
+	for _, tt := range tests {
+		if got := ArnToAccount(&tt.arn); got != tt.want {
+			t.Errorf("ArnToAccount(%q) = %q, want %q", tt.arn, got, tt.want)
+		}
+	}
 }
 
 func TestGetRegistryInfo(t *testing.T) {
@@ -186,3 +227,65 @@ func TestIsAge(t *testing.T) {
 		t.Error("IsAge(RFC3339) = true, want false")
 	}
 }
+
+func TestSparkline(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []float64
+		top    float64
+		width  int
+		want   string
+	}{
+		{
+			name:   "empty",
+			values: nil,
+			top:    100,
+			width:  10,
+			want:   "",
+		},
+		{
+			name:   "percent",
+			values: []float64{0, 50, 100, 120},
+			top:    100,
+			width:  10,
+			want:   "▁▄██",
+		},
+		{
+			name:   "scale to largest",
+			values: []float64{1, 2, 4},
+			top:    0,
+			width:  10,
+			want:   "▂▄█",
+		},
+		{
+			name:   "bucket by peak",
+			values: []float64{0, 100, 0, 0, 0, 0},
+			top:    100,
+			width:  3,
+			want:   "█▁▁",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Sparkline(tc.values, tc.top, tc.width)
+			if result != tc.want {
+				t.Errorf("Got: %s, Want: %s", result, tc.want)
+			}
+		})
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	got, err := YAMLToJSON([]byte("family: app\ncpu: \"256\"\ncontainerDefinitions:\n  - name: web\n    essential: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"containerDefinitions":[{"essential":true,"name":"web"}],"cpu":"256","family":"app"}`
+	if string(got) != want {
+		t.Errorf("Got: %s, Want: %s", got, want)
+	}
+	if _, err := YAMLToJSON([]byte("family: [app")); err == nil {
+		t.Error("Got no error, Want: invalid YAML error")
+	}
+}
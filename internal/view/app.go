@@ -1,10 +1,12 @@
 package view
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/exec"
 	"strings"
 	"time"
 
@@ -24,6 +26,15 @@ var ErrHandledNavigation = errors.New("navigation already handled")
 var globalProfile string
 var globalRegion string
 
+// Regions listed together on the clusters page, empty unless in multi-region mode
+var globalRegions []string
+
+// Profiles listed together on the clusters page, empty unless in multi-account mode
+var globalProfiles []string
+
+// Profile name shown when resources are served from fixtures
+const demoProfile = "demo"
+
 // Entity contains ECS resources to show, use uppercase to make items like app.cluster easy to access
 type Entity struct {
 	cluster           *types.Cluster
@@ -69,6 +80,24 @@ type Option struct {
 	ExecMode string
 	// Custom command template for ECS container SSM sessions.
 	SsmCustomCommand string
+	// Serve resources from the bundled demo snapshot instead of AWS
+	Demo bool
+	// Serve resources from a JSON or YAML snapshot file instead of AWS
+	Fixtures string
+	// AWS API call timeout in seconds, -1 is no timeout
+	Timeout int
+	// List clusters of several regions together
+	Regions []string
+	// Endpoint of every AWS service, e.g. LocalStack
+	EndpointURL string
+	// Endpoints by aws cli service name, override EndpointURL
+	Endpoints map[string]string
+	// Sign-in URL template opening console URLs, e.g. through an SSO portal
+	ConsoleURLTemplate string
+	// Show CPU% and Mem% columns in services table
+	ServiceUtilization bool
+	// Rendering of structured log messages
+	LogFormat LogFormat
 }
 
 // viewState holds sort/filter state per page so it can be restored after a reload.
@@ -84,10 +113,19 @@ type App struct {
 	*tview.Application
 	// Info + table area pages UI for MainScreen
 	*tview.Pages
+	// Runs UI updates from goroutines on the main loop
+	dispatcher *ui.Dispatcher
 	// Notice text UI in MainScreen footer
 	Notice *ui.Notice
+	// Spinner and data age in MainScreen footer
+	refreshStatus *ui.RefreshStatus
+	// Identity, profile and region above pages
+	contextBar *ui.ContextBar
 	// mainScreen content UI
 	mainScreen *tview.Flex
+	// Done once the app closes, stops tickers updating the UI
+	closed   context.Context
+	closeApp context.CancelFunc
 	// API client
 	*api.Store
 	// Option from cli args
@@ -114,20 +152,63 @@ type App struct {
 	fromCluster bool
 	// First paint after splash: avoid a second identical API list call.
 	bootstrapClusters []types.Cluster
+	bootstrapProfiles []string
 	bootstrapServices []types.Service
+	bootstrapErr      error
 	// Set when splash bootstrap fails before Run() returns; read after Run().
 	splashStartupErr error
 	// Persists sort/filter state per page across page reloads.
 	viewStates map[string]viewState
+	// Set once tview application runs, page loads run in background from then on
+	running bool
+	// Resource load in background, nil when idle
+	load *pendingLoad
+	// Extra content of the page on screen loading in background, e.g. utilization columns
+	contentLoad *pendingLoad
+	// Navigation state of the page on screen, restored when a load is cancelled
+	shown navState
+	// Run when the next page load fails or is cancelled, e.g. revert a profile switch
+	loadFailed func()
+	// Page loads skip the Store cache, set while a manual or auto refresh runs
+	bypassCache bool
+	// Regions marked in regions page
+	markedRegions []string
+	// Profiles marked in profiles page
+	markedProfiles []string
+	// Profile listing the selected cluster in multi-account mode
+	clusterProfile string
+	// Index of api.MetricsWindows shown in metrics page
+	metricsWindow int
+	// Container Insights utilization of listed tasks and their containers, nil when disabled
+	utilization map[string]api.Utilization
+	// Live tail of logs, kept running when going back to the table
+	tail *logTail
+	// Log page of the last resource, keeps time range, log stream and older events
+	logs *logPager
+	// Rendering of structured log messages
+	logFormat *logFormatter
+	// Last Logs Insights query, kept to edit and run again
+	insights *logsInsights
+	// Show JSON pages as a collapsible tree
+	jsonTree bool
+	// Format of description pages, index of describeFormats
+	describeFormat int
+	// Last utilization of services by service ARN, shown until reloaded values arrive
+	serviceUtilization map[string]api.Utilization
 }
 
 func newApp(option Option) (*App, error) {
 	globalProfile = os.Getenv("AWS_PROFILE")
 	globalRegion = os.Getenv("AWS_REGION")
+	globalRegions = nil
+	globalProfiles = nil
+	if len(option.Regions) > 1 {
+		globalRegions = option.Regions
+	}
 	var store *api.Store
 	var err error
 	if !option.Splash {
-		store, err = api.NewStore(globalProfile, globalRegion)
+		store, err = newStore(option)
 		if err != nil {
 			return nil, err
 		}
@@ -136,17 +217,28 @@ func newApp(option Option) (*App, error) {
 	pages := tview.NewPages()
 	footer := tview.NewFlex()
 
-	notice := ui.NewNotice(app, theme)
-	footer.AddItem(notice, 0, 1, false)
+	dispatcher := ui.NewDispatcher(app)
+	notice := ui.NewNotice(dispatcher, theme)
+	refreshStatus := ui.NewRefreshStatus(dispatcher, theme)
+	contextBar := ui.NewContextBar(dispatcher, theme)
+	footer.AddItem(notice, 0, 1, false).
+		AddItem(refreshStatus, 24, 0, false)
 	main := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(contextBar, 1, 0, false).
 		AddItem(pages, 0, 2, true).
 		AddItem(footer, 1, 1, false)
+	closed, closeApp := context.WithCancel(context.Background())
 
 	return &App{
 		Application:   app,
 		Pages:         pages,
+		dispatcher:    dispatcher,
 		Notice:        notice,
+		refreshStatus: refreshStatus,
+		contextBar:    contextBar,
 		mainScreen:    main,
+		closed:        closed,
+		closeApp:      closeApp,
 		Store:         store,
 		Option:        option,
 		kind:          ClusterKind,
@@ -167,15 +259,46 @@ func newApp(option Option) (*App, error) {
 			taskDefinition: &types.TaskDefinition{},
 		},
 		viewStates: make(map[string]viewState),
+		logFormat:  newLogFormatter(option.LogFormat),
 	}, nil
 }
 
+// Create store from AWS config, or from fixtures in demo mode
+func newStore(option Option) (*api.Store, error) {
+	var store *api.Store
+	if option.Fixtures == "" && !option.Demo {
+		var err error
+		store, err = api.NewStore(globalProfile, globalRegion, api.Endpoints{URL: option.EndpointURL, Services: option.Endpoints})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var fixtures *api.Fixtures
+		var err error
+		if option.Fixtures != "" {
+			fixtures, err = api.LoadFixtures(option.Fixtures)
+		} else {
+			fixtures, err = api.DemoFixtures()
+		}
+		if err != nil {
+			return nil, err
+		}
+		globalProfile = demoProfile
+		globalRegion = fixtures.Region
+		store = api.NewFixtureStore(fixtures)
+	}
+	if option.Timeout > 0 {
+		store.Timeout = time.Duration(option.Timeout) * time.Second
+	}
+	return store, nil
+}
+
 func (app *App) viewStateKey() string {
 	return app.kind.getAppPageName(app.getPageHandle())
 }
 
 func (app *App) canAutoRefresh() bool {
-	return app.secondaryKind == EmptyKind && !app.isSuspended && !app.filterInputActive
+	return app.secondaryKind == EmptyKind && !app.isSuspended && !app.filterInputActive && app.load == nil
 }
 
 // Entry point of the app
@@ -198,6 +321,7 @@ func Start(option Option) error {
 
 	if option.Splash {
 		app.SetRoot(app.buildSplashPage(), true)
+		app.running = true
 		go app.runSplashBootstrap()
 		if err := app.Application.Run(); err != nil {
 			return err
@@ -209,6 +333,7 @@ func Start(option Option) error {
 		if err := app.start(); err != nil {
 			return err
 		}
+		app.running = true
 		if err := app.Application.SetRoot(app.mainScreen, true).Run(); err != nil {
 			return err
 		}
@@ -224,6 +349,7 @@ func (app *App) addAppPage(page *tview.Flex) {
 	slog.Debug("app.Pages navigation", "action", "AppPage", "pageName", pageName, "app", app)
 
 	app.Pages.AddPage(pageName, page, true, true)
+	app.markShown()
 }
 
 // Switch app.Pages page
@@ -233,6 +359,7 @@ func (app *App) switchPage(reload bool) bool {
 
 		slog.Debug("app.Pages navigation", "action", "SwitchToPage", "pageName", pageName, "app", app)
 		app.Pages.SwitchToPage(pageName)
+		app.markShown()
 		return true
 	}
 	return false
@@ -279,6 +406,7 @@ func (app *App) back() {
 	}
 
 	app.Pages.SwitchToPage(pageName)
+	app.markShown()
 }
 
 // Get page handler, cluster is empty, other is cluster arn
@@ -306,6 +434,9 @@ func (app *App) getPageHandle() string {
 
 func (app *App) start() error {
 	var err error
+	app.refreshStatus.Run(app.closed)
+	app.contextBar.Run(app.closed)
+	app.refreshContext()
 	if app.Option.Cluster == "" {
 		err = app.showPrimaryKindPage(ClusterKind, false)
 	} else {
@@ -323,20 +454,14 @@ func (app *App) start() error {
 		ticker := time.NewTicker(time.Duration(app.Option.Refresh) * time.Second)
 
 		go func() {
+			defer ticker.Stop()
 			for {
-				<-ticker.C
-				if app.secondaryKind == EmptyKind && !app.isSuspended {
-					// tview is not thread-safe: UI updates must run on the main loop
-					app.QueueUpdateDraw(func() {
-						if !app.canAutoRefresh() {
-							slog.Debug("Auto refresh skipped")
-							return
-						}
-						if err := app.showPrimaryKindPage(app.kind, true); err != nil {
-							// showPrimaryKindPage already shows error in Notice
-						}
-						slog.Debug("Auto refresh")
-					})
+				select {
+				case <-app.closed.Done():
+					return
+				case <-ticker.C:
+					// The reload only starts a background load, the loop stays responsive
+					app.dispatch(app.autoRefresh)
 				}
 			}
 		}()
@@ -344,6 +469,21 @@ func (app *App) start() error {
 	return err
 }
 
+// Reload current primary page, skipping cached responses so the refreshed age in
+// the footer is the age of the data
+func (app *App) autoRefresh() {
+	if !app.canAutoRefresh() {
+		slog.Debug("Auto refresh skipped")
+		return
+	}
+	app.bypassCache = true
+	if err := app.showPrimaryKindPage(app.kind, true); err != nil {
+		// showPrimaryKindPage already shows error in Notice
+	}
+	app.bypassCache = false
+	slog.Debug("Auto refresh")
+}
+
 // Show Primary kind page
 func (app *App) showPrimaryKindPage(k kind, reload bool) error {
 	var err error
@@ -370,7 +510,20 @@ func (app *App) showPrimaryKindPage(k kind, reload bool) error {
 		app.kind = ClusterKind
 		err = app.showClustersPage(reload)
 	}
+	if errors.Is(err, errPageLoading) {
+		return nil
+	}
+	return app.pageShown(err, reload)
+}
+
+// Report result of showing a page in Notice
+func (app *App) pageShown(err error, reload bool) error {
 	if err != nil {
+		if isPartial(err) {
+			slog.Warn("show partial results", "error", err)
+			app.Notice.Warnf("Showing partial %s list, %v", app.kind.String(), errors.Unwrap(err))
+			return nil
+		}
 		if errors.Is(err, ErrHandledNavigation) {
 			// A valid page has already been shown (for example, fallback from empty
 			// clusters to regions), so skip noisy error notice.
@@ -381,7 +534,8 @@ func (app *App) showPrimaryKindPage(k kind, reload bool) error {
 		return err
 	}
 	if !reload {
-		if app.taskStatus != types.DesiredStatusStopped {
+		// Profiles and regions pages are shown without notice
+		if app.taskStatus != types.DesiredStatusStopped && app.kind != ProfileKind && app.kind != RegionKind {
 			app.Notice.Infof("Viewing %s...", app.kind.String())
 		}
 	} else {
@@ -390,14 +544,141 @@ func (app *App) showPrimaryKindPage(k kind, reload bool) error {
 	return nil
 }
 
+// Regions shown on the clusters page
+func regionsLabel() string {
+	if len(globalRegions) > 0 {
+		return strings.Join(globalRegions, ",")
+	}
+	return globalRegion
+}
+
+// Profiles shown on the clusters page
+func profilesLabel() string {
+	if len(globalProfiles) > 0 {
+		return strings.Join(globalProfiles, ",")
+	}
+	return globalProfile
+}
+
+// In multi-region or multi-account mode resources of a cluster are served with the
+// cluster's profile and region, then show runs. Another profile's credentials load in background.
+func (app *App) useClusterStore(cluster *types.Cluster, profile string, show func()) {
+	if len(globalRegions) == 0 && len(globalProfiles) == 0 {
+		show()
+		return
+	}
+	use := func(store *api.Store) {
+		if profile != "" {
+			globalProfile = profile
+		}
+		if region := utils.ArnToRegion(cluster.ClusterArn); region != "" {
+			globalRegion = region
+			store = store.Regional(region)
+		}
+		if store != app.Store {
+			slog.Debug("Use cluster store", "profile", globalProfile, "region", globalRegion)
+			app.Store = store
+			app.refreshContext()
+		}
+		show()
+	}
+	home := app.Store.Home()
+	if profile == "" {
+		use(home)
+		return
+	}
+
+	var store *api.Store
+	app.loadSecondaryPage(func(ctx context.Context) (err error) {
+		store, err = home.ForProfile(ctx, profile)
+		return err
+	}, func(err error) error {
+		if err != nil {
+			app.Notice.Warnf("failed to use profile %s, err: %v", profile, err)
+			return err
+		}
+		use(store)
+		return nil
+	})
+}
+
+// Show who the store in use acts as in the context line
+func (app *App) refreshContext() {
+	store := app.Store
+	info := ui.ContextInfo{Profile: globalProfile, Region: globalRegion}
+	app.contextBar.Set(info)
+	go func() {
+		identity, err := store.GetIdentity(context.Background())
+		app.dispatch(func() {
+			// Switched to another store meanwhile
+			if app.Store != store {
+				return
+			}
+			if err != nil {
+				slog.Warn("failed to get caller identity", "error", err)
+				return
+			}
+			info.Account = identity.Account
+			info.AccountAlias = identity.AccountAlias
+			info.Principal = identity.Principal
+			info.Session = identity.Session
+			info.Expires = identity.Expires
+			app.contextBar.Set(info)
+		})
+	}()
+}
+
+// Arguments of an aws cli command, args[0] is the service, with the profile, the region
+// and the endpoint of the store in use so the cli talks to the same API as e1s
+func (app *App) cliArgs(args []string) []string {
+	global := []string{}
+	if app.Store == nil || app.Store.IsFixture() {
+		return args
+	}
+	if profile := app.Store.Profile(); profile != "" {
+		global = append(global, "--profile", profile)
+	}
+	if app.Store.Region != "" {
+		global = append(global, "--region", app.Store.Region)
+	}
+	if len(args) > 0 {
+		if url := app.Store.Endpoints.For(args[0]); url != "" {
+			global = append(global, "--endpoint-url", url)
+		}
+	}
+	return append(global, args...)
+}
+
+// Aws cli command of args, see cliArgs. ecs execute-command starts session-manager-plugin
+// with the SSM endpoint of the cli, the ssm endpoint in use is passed through its environment.
+func (app *App) cliCommand(bin string, args []string) *exec.Cmd {
+	cmd := exec.Command(bin, app.cliArgs(args)...)
+	if app.Store == nil || app.Store.IsFixture() || len(args) == 0 || args[0] == "ssm" {
+		return cmd
+	}
+	if url := app.Store.Endpoints.For("ssm"); url != "" {
+		cmd.Env = append(os.Environ(), "AWS_ENDPOINT_URL_SSM="+url)
+	}
+	return cmd
+}
+
+// Run f on the main loop from any goroutine, tview is not thread-safe
+func (app *App) dispatch(f func()) {
+	app.dispatcher.Dispatch(f)
+}
+
 // E1s app close hook
 func (app *App) onClose() {
+	app.closeApp()
+	app.stopContentLoad()
+	app.stopLogTail()
+	app.stopLogsInsights()
 	if len(app.sessions) != 0 {
 		ids := []*string{}
 		for _, s := range app.sessions {
 			ids = append(ids, s.sessionId)
 		}
-		err := app.Store.TerminateSessions(ids)
+		err := app.Store.TerminateSessions(context.Background(), ids)
 		if err != nil {
 			slog.Error("Failed to terminated port forwarding sessions", "error", err)
 		} else {
@@ -419,8 +700,27 @@ func (app *App) globalInputHandle(event *tcell.EventKey) *tcell.EventKey {
 		}
 	}
 
-	switch event.Rune() {
-	case '?':
+	if app.load != nil {
+		switch {
+		case event.Key() == tcell.KeyCtrlC:
+		case event.Key() == tcell.KeyEsc && !app.load.reload:
+			app.cancelLoad()
+			app.Notice.Info("Cancelled loading")
+			return nil
+		case event.Key() == tcell.KeyEsc && !app.filterInputActive:
+			// Navigate away from the page being reloaded
+			app.stopLoad()
+		case event.Key() == tcell.KeyCtrlP, event.Key() == tcell.KeyCtrlR, event.Rune() == '?':
+			// Navigate away from the page being loaded
+			app.cancelLoad()
+		case !app.load.reload:
+			// Other keys would act on the previous page still on screen
+			return nil
+		}
+	}
+
+	// ? is typed into filter and search inputs
+	if _, typing := app.GetFocus().(*tview.InputField); event.Rune() == '?' && !typing {
 		app.showHelpPage()
 	}
 
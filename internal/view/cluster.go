@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -14,6 +15,8 @@ import (
 type clusterView struct {
 	view
 	clusters []types.Cluster
+	// Profile listing each cluster in multi-account mode
+	profiles []string
 }
 
 func newClusterView(clusters []types.Cluster, app *App) *clusterView {
@@ -36,17 +39,37 @@ func (app *App) showClustersPage(reload bool) error {
 	}
 
 	var resources []types.Cluster
-	var err error
-	if len(app.bootstrapClusters) > 0 && !reload {
-		resources = app.bootstrapClusters
-		app.bootstrapClusters = nil
-	} else {
-		resources, err = app.Store.ListClusters()
+	var clusterProfiles []string
+	bootstrapped := len(app.bootstrapClusters) > 0 && !reload
+	if bootstrapped {
+		resources, clusterProfiles = app.bootstrapClusters, app.bootstrapProfiles
+		app.bootstrapClusters, app.bootstrapProfiles = nil, nil
 	}
-	err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
-		return newClusterView(resources, app)
+	bootstrapErr := app.bootstrapErr
+	app.bootstrapErr = nil
+	profiles, regions := globalProfiles, globalRegions
+	store := app.Store
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		if bootstrapped {
+			return bootstrapErr
+		}
+		if len(profiles) > 0 {
+			resources, clusterProfiles, err = store.Home().ListClustersInProfiles(ctx, profiles, regions)
+			return err
+		}
+		if len(regions) > 0 {
+			resources, err = store.ListClustersInRegions(ctx, regions)
+			return err
+		}
+		resources, err = store.ListClusters(ctx)
+		return err
+	}, func(err error) error {
+		return buildResourcePage(resources, app, err, func() resourceViewBuilder {
+			view := newClusterView(resources, app)
+			view.profiles = clusterProfiles
+			return view
+		})
 	})
-	return err
 }
 
 func (v *clusterView) getViewAndFooter() (*view, *tview.TextView) {
@@ -68,10 +91,7 @@ func (v *clusterView) headerParamsBuilder() []headerPageParam {
 // Generate info pages params
 func (v *clusterView) headerPageItems(index int) (items []headerItem) {
 	c := v.clusters[index]
-	containerInsights := "disabled"
-	if len(c.Settings) > 0 && c.Settings[0].Name == "containerInsights" {
-		containerInsights = *c.Settings[0].Value
-	}
+	containerInsights := containerInsightsSetting(&c)
 	// ServiceConnectDefaults
 	scd := utils.EmptyText
 	if c.ServiceConnectDefaults != nil {
@@ -130,6 +150,8 @@ func (v *clusterView) headerPageItems(index int) (items []headerItem) {
 		{name: "Execute command configuration", value: ecc},
 		{name: "Managed storage configuration", value: msc},
 		{name: "Tags count", value: strconv.Itoa(len(c.Tags))},
+		{name: "Region", value: utils.ArnToRegion(c.ClusterArn)},
+		{name: "Account", value: utils.ArnToAccount(c.ClusterArn)},
 	}
 	return
 }
@@ -137,22 +159,41 @@ func (v *clusterView) headerPageItems(index int) (items []headerItem) {
 // Generate table params
 func (v *clusterView) tableParamsBuilder() (title string, headers []string, rowsBuilder func() [][]string) {
 	title = fmt.Sprintf(color.TableTitleFmt, v.app.kind, "all", len(v.clusters))
-	headers = []string{
-		"Name",
+	multiRegion := len(globalRegions) > 0
+	multiAccount := len(globalProfiles) > 0
+	headers = []string{"Name"}
+	if multiAccount {
+		headers = append(headers, "Profile", "Account")
+	}
+	if multiRegion {
+		headers = append(headers, "Region")
+	}
+	headers = append(headers, []string{
 		"Status",
 		"Services",
 		"Tasks",
 		"Container instances",
 		"Capacity providers",
-	}
+	}...)
 
 	rowsBuilder = func() (data [][]string) {
-		for _, c := range v.clusters {
+		for i, c := range v.clusters {
+			profile := ""
+			if i < len(v.profiles) {
+				profile = v.profiles[i]
+			}
 			// calculate tasks
 			tasks := fmt.Sprintf(color.TableClusterTasksFmt, c.PendingTasksCount, c.RunningTasksCount)
 
 			row := []string{}
 			row = append(row, utils.ShowString(c.ClusterName))
+			if multiAccount {
+				row = append(row, profile)
+				row = append(row, utils.ArnToAccount(c.ClusterArn))
+			}
+			if multiRegion {
+				row = append(row, utils.ArnToRegion(c.ClusterArn))
+			}
 			row = append(row, utils.ShowGreenGrey(c.Status, "active"))
 			row = append(row, utils.ShowInt(&c.ActiveServicesCount))
 			row = append(row, tasks)
@@ -161,10 +202,25 @@ func (v *clusterView) tableParamsBuilder() (title string, headers []string, rows
 
 			data = append(data, row)
 
-			entity := Entity{cluster: &c, entityName: *c.ClusterArn}
+			entity := Entity{cluster: &c, profile: profile, entityName: *c.ClusterArn}
 			v.originalRowReferences = append(v.originalRowReferences, entity)
 		}
 		return data
 	}
 	return
 }
+
+// Container insights setting of cluster, "disabled" when not set
+func containerInsightsSetting(c *types.Cluster) string {
+	for _, s := range c.Settings {
+		if s.Name == types.ClusterSettingNameContainerInsights && s.Value != nil {
+			return *s.Value
+		}
+	}
+	return "disabled"
+}
+
+// Task and container metrics are only published with enhanced observability
+func containerInsightsEnhanced(c *types.Cluster) bool {
+	return c != nil && containerInsightsSetting(c) == "enhanced"
+}
@@ -1,6 +1,8 @@
 package view
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -135,3 +137,83 @@ func TestClusterTableParamsBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterTableMultiAccount(t *testing.T) {
+	clusterViews := getClusterViews()
+	v := clusterViews[0]
+	globalProfiles = []string{"dev", "prod"}
+	defer func() { globalProfiles = nil }()
+	v.profiles = []string{"prod"}
+
+	_, headers, rowsBuilder := v.tableParamsBuilder()
+	if headers[1] != "Profile" || headers[2] != "Account" {
+		t.Fatalf("Got headers %v, Want: Profile and Account after Name", headers)
+	}
+	row := rowsBuilder()[0]
+	if row[1] != "prod" {
+		t.Errorf("Profile Got: %s, Want: prod", row[1])
+	}
+	if row[2] != "111111" {
+		t.Errorf("Account Got: %s, Want: 111111", row[2])
+	}
+
+	// the same cluster reached by two profiles keeps the profile of each row
+	v = *newClusterView([]types.Cluster{v.clusters[0], v.clusters[0]}, v.app)
+	v.profiles = []string{"dev", "prod"}
+	v.buildTable(v.tableParamsBuilder())
+	for r := 1; r < v.table.GetRowCount(); r++ {
+		v.table.Select(r, 0)
+		want := v.table.GetCell(r, 1).Text
+		if v.app.clusterProfile != want {
+			t.Errorf("Row %d profile Got: %s, Want: %s", r, v.app.clusterProfile, want)
+		}
+	}
+	if v.table.GetRowCount() != 3 || v.table.GetCell(1, 1).Text == v.table.GetCell(2, 1).Text {
+		t.Errorf("Got %d rows, Want: one row per profile", v.table.GetRowCount()-1)
+	}
+}
+
+func TestMultiRegionClusters(t *testing.T) {
+	app := demoApp(t, Option{Regions: []string{"us-east-1", "eu-west-1"}})
+	if err := app.showPrimaryKindPage(ClusterKind, false); err != nil {
+		t.Fatalf("show clusters page: %v", err)
+	}
+
+	ctx := context.Background()
+	single, _ := app.Store.ListClusters(ctx)
+	if len(single) != 2 {
+		t.Fatalf("Got %d clusters in us-east-1, Want: 2", len(single))
+	}
+	clusters, err := app.Store.ListClustersInRegions(ctx, globalRegions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 3 || *clusters[2].ClusterName != "demo-eu" {
+		t.Fatalf("Got %d clusters, Want: 3 ending with demo-eu", len(clusters))
+	}
+
+	// moving the cursor keeps the store, entering the cluster switches to its region
+	v := newClusterView(clusters, app)
+	v.buildTable(v.tableParamsBuilder())
+	row := 0
+	for r := 1; r < v.table.GetRowCount(); r++ {
+		if strings.Contains(v.table.GetCell(r, 0).Text, "demo-eu") {
+			row = r
+		}
+	}
+	v.table.Select(row, 0)
+	if *app.cluster.ClusterName != "demo-eu" || app.Store.Region != "us-east-1" {
+		t.Fatalf("Got cluster %s in store region %s, Want: demo-eu in us-east-1", *app.cluster.ClusterName, app.Store.Region)
+	}
+	v.handleSelected(row, 0)
+	if app.Store.Region != "eu-west-1" || globalRegion != "eu-west-1" {
+		t.Fatalf("Got store region %s, Want: eu-west-1", app.Store.Region)
+	}
+	if app.kind != ServiceKind {
+		t.Fatalf("Got kind %s, Want: services page", app.kind)
+	}
+	services, _ := app.Store.ListServices(ctx, app.cluster.ClusterName)
+	if len(services) != 1 {
+		t.Errorf("Got %d services, Want: 1", len(services))
+	}
+}
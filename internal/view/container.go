@@ -28,8 +28,10 @@ func newContainerView(containers []types.Container, app *App) *containerView {
 	}...)
 	return &containerView{
 		view: *newView(app, keys, secondaryPageKeyMap{
-			DescriptionKind: describePageKeys,
-			LogKind:         logPageKeys,
+			DescriptionKind:  describePageKeys,
+			LogKind:          logPageKeys,
+			LogTailKind:      logTailPageKeys,
+			LogsInsightsKind: logsInsightsPageKeys,
 		}),
 		containers: containers,
 	}
@@ -111,6 +113,9 @@ func (v *containerView) tableParamsBuilder() (title string, headers []string, ro
 		"Registry",
 		"Image name",
 	}
+	if v.app.utilization != nil {
+		headers = append(headers, "CPU%", "Mem%")
+	}
 
 	rowsBuilder = func() (data [][]string) {
 		for _, c := range v.containers {
@@ -136,6 +141,9 @@ func (v *containerView) tableParamsBuilder() (title string, headers []string, ro
 			row = append(row, portText)
 			row = append(row, registry)
 			row = append(row, imageName)
+			if v.app.utilization != nil {
+				row = append(row, utilizationCells(v.app.utilization, utils.ArnToName(v.app.task.TaskArn)+"/"+utils.ShowString(c.Name))...)
+			}
 			data = append(data, row)
 
 			entity := Entity{container: &c, entityName: *c.ContainerArn}
@@ -0,0 +1,170 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
+	"github.com/keidarcy/e1s/internal/color"
+)
+
+// App on the demo fixtures with option and styles like Start, globals are restored after the test
+func demoApp(t *testing.T, option Option) *App {
+	t.Helper()
+	oldTheme, profile, region, profiles, regions := theme, globalProfile, globalRegion, globalProfiles, globalRegions
+	t.Cleanup(func() {
+		theme, globalProfile, globalRegion, globalProfiles, globalRegions = oldTheme, profile, region, profiles, regions
+	})
+	theme = color.InitStyles(option.Theme)
+	option.Demo = true
+	option.Refresh = -1
+	app, err := newApp(option)
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+	return app
+}
+
+// Services table of the demo-production cluster
+func demoServiceView(t *testing.T, option Option) *serviceView {
+	t.Helper()
+	app := demoApp(t, option)
+	ctx := context.Background()
+	clusters, err := app.Store.ListClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.cluster = findCluster(clusters, "demo-production"); app.cluster == nil {
+		t.Fatal("demo-production cluster not found")
+	}
+	services, err := app.Store.ListServices(ctx, app.cluster.ClusterName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.kind = ServiceKind
+	v := newServiceView(services, app)
+	v.buildTable(v.tableParamsBuilder())
+	return v
+}
+
+func findCluster(clusters []types.Cluster, name string) *types.Cluster {
+	for i := range clusters {
+		if *clusters[i].ClusterName == name {
+			return &clusters[i]
+		}
+	}
+	return nil
+}
+
+func TestDemoDrillDown(t *testing.T) {
+	app := demoApp(t, Option{})
+	if !app.Store.IsFixture() {
+		t.Fatal("expected fixture store in demo mode")
+	}
+
+	showPage := func(k kind) {
+		t.Helper()
+		if err := app.showPrimaryKindPage(k, false); err != nil {
+			t.Fatalf("show %s page: %v", k, err)
+		}
+		if name := k.getAppPageName(app.getPageHandle()); !app.Pages.HasPage(name) {
+			t.Fatalf("page %s not added", name)
+		}
+	}
+
+	showPage(ClusterKind)
+	clusters, _ := app.Store.ListClusters(context.Background())
+	if app.cluster = findCluster(clusters, "demo-production"); app.cluster == nil {
+		t.Fatal("demo-production cluster not found")
+	}
+
+	showPage(ServiceKind)
+	services, _ := app.Store.ListServices(context.Background(), app.cluster.ClusterName)
+	if len(services) != 3 {
+		t.Fatalf("Got %d services, Want: 3", len(services))
+	}
+	for i := range services {
+		if *services[i].ServiceName == "web" {
+			app.service = &services[i]
+		}
+	}
+
+	showPage(TaskKind)
+	tasks, _, _ := app.Store.ListTasks(context.Background(), app.cluster.ClusterName, app.service.ServiceName, types.DesiredStatusRunning)
+	if len(tasks) != 2 {
+		t.Fatalf("Got %d tasks, Want: 2", len(tasks))
+	}
+	app.task = &tasks[0]
+
+	showPage(ContainerKind)
+	showPage(TaskDefinitionKind)
+	app.kind = ServiceKind
+	showPage(ServiceDeploymentKind)
+}
+
+func TestFixturesFromYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.yml")
+	snapshot := `
+Region: eu-west-1
+Clusters:
+  - ClusterName: yaml-cluster
+    ClusterArn: arn:aws:ecs:eu-west-1:111111:cluster/yaml-cluster
+    Status: ACTIVE
+`
+	if err := os.WriteFile(path, []byte(snapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := newApp(Option{Fixtures: path, Refresh: -1})
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+	if globalRegion != "eu-west-1" {
+		t.Errorf("Got region: %s, Want: eu-west-1", globalRegion)
+	}
+	clusters, err := app.Store.ListClusters(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 || *clusters[0].ClusterName != "yaml-cluster" {
+		t.Errorf("Got clusters: %v, Want: yaml-cluster", clusters)
+	}
+}
+
+func TestListServicesKeepsOrder(t *testing.T) {
+	clusterArn := "arn:aws:ecs:us-east-1:111111:cluster/big"
+	fixtures := &api.Fixtures{
+		Region:   "us-east-1",
+		Clusters: []types.Cluster{{ClusterName: aws.String("big"), ClusterArn: aws.String(clusterArn)}},
+	}
+	for i := range 95 {
+		name := fmt.Sprintf("svc-%02d", i)
+		fixtures.Services = append(fixtures.Services, types.Service{
+			ServiceName: aws.String(name),
+			ServiceArn:  aws.String("arn:aws:ecs:us-east-1:111111:service/big/" + name),
+			ClusterArn:  aws.String(clusterArn),
+		})
+	}
+	store := api.NewFixtureStore(fixtures)
+
+	// Refreshes must not reorder rows
+	for range 5 {
+		services, err := store.ListServices(context.Background(), aws.String("big"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(services) != len(fixtures.Services) {
+			t.Fatalf("Got %d services, Want: %d", len(services), len(fixtures.Services))
+		}
+		for i := range services {
+			if *services[i].ServiceName != *fixtures.Services[i].ServiceName {
+				t.Fatalf("Got service %s at %d, Want: %s", *services[i].ServiceName, i, *fixtures.Services[i].ServiceName)
+			}
+		}
+	}
+}
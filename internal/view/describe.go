@@ -0,0 +1,367 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/utils"
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of description pages, switched with y
+const (
+	describeJSON = iota
+	describeYAML
+	describeText
+)
+
+var describeFormats = []string{"json", "yaml", "describe"}
+
+// "key:" at line start of YAML and describe text, after list item dash. Words of keys
+// are single spaced, describe table rows and events are not matched.
+var describeKeyRX = regexp.MustCompile(`^(\s*(?:- )?)([A-Za-z@_#][\w./@#-]*(?: [\w./@#-]+)*):(\s|$)`)
+
+// Content of description page in selected format, the raw content is copied and edited
+func (v *view) getDescribeString(entity Entity) (string, []byte, error) {
+	colorizedJsonString, jsonBytes, err := v.getJsonString(entity)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch v.app.describeFormat {
+	case describeYAML:
+		yamlBytes, err := jsonToYAML(jsonBytes)
+		if err != nil {
+			v.app.Notice.Warnf("failed to convert JSON to YAML, error: %v", err)
+			return "", nil, err
+		}
+		return colorizeKeys(string(yamlBytes)), yamlBytes, nil
+	case describeText:
+		text, err := v.describeText(entity, jsonBytes)
+		if err != nil {
+			v.app.Notice.Warnf("failed to describe, error: %v", err)
+			return "", nil, err
+		}
+		return colorizeKeys(text), []byte(text), nil
+	}
+	return colorizedJsonString, jsonBytes, nil
+}
+
+// Switch description page to next format
+func (v *view) switchDescribeFormat(entity Entity) {
+	v.app.describeFormat = (v.app.describeFormat + 1) % len(describeFormats)
+	v.showJsonPages(entity)
+	v.app.Notice.Infof("Viewing %s as %s", v.app.secondaryKind, describeFormats[v.app.describeFormat])
+}
+
+// YAML of JSON keeping key order
+func jsonToYAML(jsonBytes []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonBytes, &node); err != nil {
+		return nil, err
+	}
+	// JSON is flow style YAML with quoted strings, use block style and plain strings
+	var reset func(n *yaml.Node)
+	reset = func(n *yaml.Node) {
+		n.Style = 0
+		for _, c := range n.Content {
+			reset(c)
+		}
+	}
+	reset(&node)
+
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Escape text and color keys at line start like colorizeJSON
+func colorizeKeys(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		l = tview.Escape(l)
+		if res := describeKeyRX.FindStringSubmatch(l); len(res) == 4 {
+			l = fmt.Sprintf("%s[%s::b]%s[-:-:-]:%s", res[1], theme.Blue, res[2], l[len(res[0])-len(res[3]):])
+		}
+		lines[i] = l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// kubectl describe like text of entity, kinds without their own rendering list JSON fields
+func (v *view) describeText(entity Entity, jsonBytes []byte) (string, error) {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	switch {
+	case v.app.secondaryKind == DescriptionKind && v.app.kind == ServiceKind && entity.service != nil:
+		describeService(w, entity.service)
+	case v.app.secondaryKind == DescriptionKind && v.app.kind == TaskKind && entity.task != nil:
+		describeTask(w, entity.task)
+	case v.app.secondaryKind == DescriptionKind && v.app.kind == TaskDefinitionKind && entity.taskDefinition != nil:
+		describeTaskDefinition(w, entity.taskDefinition)
+	default:
+		root, err := parseJSONNode("", jsonBytes)
+		if err != nil {
+			return "", err
+		}
+		describeJSONNode(w, root, "")
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func describeService(w *tabwriter.Writer, s *types.Service) {
+	launchType := string(s.LaunchType)
+	if len(s.CapacityProviderStrategy) > 0 {
+		providers := []string{}
+		for _, p := range s.CapacityProviderStrategy {
+			providers = append(providers, fmt.Sprintf("%s(base %d, weight %d)", aws.ToString(p.CapacityProvider), p.Base, p.Weight))
+		}
+		launchType = strings.Join(providers, ", ")
+	}
+	fmt.Fprintf(w, "Name:\t%s\n", utils.ShowString(s.ServiceName))
+	fmt.Fprintf(w, "Cluster:\t%s\n", utils.ArnToName(s.ClusterArn))
+	fmt.Fprintf(w, "Status:\t%s\n", utils.ShowString(s.Status))
+	fmt.Fprintf(w, "Task Definition:\t%s\n", utils.ArnToName(s.TaskDefinition))
+	fmt.Fprintf(w, "Launch Type:\t%s\n", showValue(launchType))
+	fmt.Fprintf(w, "Tasks:\t%d desired, %d running, %d pending\n", s.DesiredCount, s.RunningCount, s.PendingCount)
+	fmt.Fprintf(w, "Execute Command:\t%s\n", enabledText(s.EnableExecuteCommand))
+	fmt.Fprintf(w, "Created:\t%s\n", showTimeAge(s.CreatedAt))
+
+	fmt.Fprintf(w, "Deployments:\n")
+	if len(s.Deployments) == 0 {
+		fmt.Fprintf(w, "  %s\n", utils.EmptyText)
+	} else {
+		fmt.Fprintf(w, "  ID\tStatus\tRollout\tDesired\tRunning\tPending\tFailed\tTask Definition\tUpdated\n")
+		for _, d := range s.Deployments {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\n", utils.ShowString(d.Id), utils.ShowString(d.Status), showValue(string(d.RolloutState)), d.DesiredCount, d.RunningCount, d.PendingCount, d.FailedTasks, utils.ArnToName(d.TaskDefinition), utils.Age(d.UpdatedAt))
+		}
+	}
+
+	fmt.Fprintf(w, "Load Balancers:\n")
+	if len(s.LoadBalancers) == 0 {
+		fmt.Fprintf(w, "  %s\n", utils.EmptyText)
+	} else {
+		fmt.Fprintf(w, "  Target Group\tLoad Balancer\tContainer\tPort\n")
+		for _, lb := range s.LoadBalancers {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", targetGroupName(lb.TargetGroupArn), utils.ShowString(lb.LoadBalancerName), utils.ShowString(lb.ContainerName), utils.ShowInt(lb.ContainerPort))
+		}
+	}
+
+	fmt.Fprintf(w, "Network:\n")
+	if s.NetworkConfiguration == nil || s.NetworkConfiguration.AwsvpcConfiguration == nil {
+		fmt.Fprintf(w, "  %s\n", utils.EmptyText)
+	} else {
+		vpc := s.NetworkConfiguration.AwsvpcConfiguration
+		fmt.Fprintf(w, "  Subnets:\t%s\n", utils.ShowArray(vpc.Subnets))
+		fmt.Fprintf(w, "  Security Groups:\t%s\n", utils.ShowArray(vpc.SecurityGroups))
+		fmt.Fprintf(w, "  Public IP:\t%s\n", showValue(string(vpc.AssignPublicIp)))
+	}
+
+	// events are latest first
+	const summaryEvents = 5
+	fmt.Fprintf(w, "Events:\n")
+	if len(s.Events) == 0 {
+		fmt.Fprintf(w, "  %s\n", utils.EmptyText)
+	}
+	for i, e := range s.Events {
+		if i == summaryEvents {
+			fmt.Fprintf(w, "  (%d more, press w to show service events)\n", len(s.Events)-summaryEvents)
+			break
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", utils.Age(e.CreatedAt), utils.ShowString(e.Message))
+	}
+}
+
+func describeTask(w *tabwriter.Writer, t *types.Task) {
+	fmt.Fprintf(w, "Task:\t%s\n", utils.ArnToName(t.TaskArn))
+	fmt.Fprintf(w, "Cluster:\t%s\n", utils.ArnToName(t.ClusterArn))
+	fmt.Fprintf(w, "Status:\t%s (desired %s)\n", utils.ShowString(t.LastStatus), utils.ShowString(t.DesiredStatus))
+	fmt.Fprintf(w, "Health:\t%s\n", showValue(string(t.HealthStatus)))
+	fmt.Fprintf(w, "Task Definition:\t%s\n", utils.ArnToName(t.TaskDefinitionArn))
+	fmt.Fprintf(w, "Group:\t%s\n", utils.ShowString(t.Group))
+	fmt.Fprintf(w, "Launch Type:\t%s\n", showValue(string(t.LaunchType)))
+	fmt.Fprintf(w, "CPU/Memory:\t%s/%s\n", utils.ShowString(t.Cpu), utils.ShowString(t.Memory))
+	fmt.Fprintf(w, "Availability Zone:\t%s\n", utils.ShowString(t.AvailabilityZone))
+	fmt.Fprintf(w, "Started:\t%s\n", showTimeAge(t.StartedAt))
+	if t.StoppedAt != nil || t.StoppedReason != nil {
+		fmt.Fprintf(w, "Stopped:\t%s\n", showTimeAge(t.StoppedAt))
+		fmt.Fprintf(w, "Stop Code:\t%s\n", showValue(string(t.StopCode)))
+		fmt.Fprintf(w, "Stopped Reason:\t%s\n", utils.ShowString(t.StoppedReason))
+	}
+
+	fmt.Fprintf(w, "Containers:\n")
+	if len(t.Containers) == 0 {
+		fmt.Fprintf(w, "  %s\n", utils.EmptyText)
+	} else {
+		fmt.Fprintf(w, "  Name\tImage\tStatus\tHealth\tExit Code\tReason\n")
+		for _, c := range t.Containers {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n", utils.ShowString(c.Name), utils.ShowString(c.Image), utils.ShowString(c.LastStatus), showValue(string(c.HealthStatus)), utils.ShowInt(c.ExitCode), utils.ShowString(c.Reason))
+		}
+	}
+
+	fmt.Fprintf(w, "Attachments:\n")
+	if len(t.Attachments) == 0 {
+		fmt.Fprintf(w, "  %s\n", utils.EmptyText)
+	}
+	for _, a := range t.Attachments {
+		fmt.Fprintf(w, "  %s:\t%s (%s)\n", utils.ShowString(a.Type), utils.ShowString(a.Status), utils.ShowString(a.Id))
+		for _, d := range a.Details {
+			fmt.Fprintf(w, "    %s:\t%s\n", utils.ShowString(d.Name), utils.ShowString(d.Value))
+		}
+	}
+}
+
+func describeTaskDefinition(w *tabwriter.Writer, td *types.TaskDefinition) {
+	compatibilities := []string{}
+	for _, c := range td.RequiresCompatibilities {
+		compatibilities = append(compatibilities, string(c))
+	}
+	fmt.Fprintf(w, "Family:\t%s:%d\n", utils.ShowString(td.Family), td.Revision)
+	fmt.Fprintf(w, "Status:\t%s\n", showValue(string(td.Status)))
+	fmt.Fprintf(w, "Network Mode:\t%s\n", showValue(string(td.NetworkMode)))
+	fmt.Fprintf(w, "CPU/Memory:\t%s/%s\n", utils.ShowString(td.Cpu), utils.ShowString(td.Memory))
+	fmt.Fprintf(w, "Compatibilities:\t%s\n", utils.ShowArray(compatibilities))
+	fmt.Fprintf(w, "Task Role:\t%s\n", utils.ArnToName(td.TaskRoleArn))
+	fmt.Fprintf(w, "Execution Role:\t%s\n", utils.ArnToName(td.ExecutionRoleArn))
+	fmt.Fprintf(w, "Registered:\t%s\n", showTimeAge(td.RegisteredAt))
+
+	fmt.Fprintf(w, "Containers:\n")
+	if len(td.ContainerDefinitions) == 0 {
+		fmt.Fprintf(w, "  %s\n", utils.EmptyText)
+	}
+	for _, c := range td.ContainerDefinitions {
+		memory := utils.ShowInt(c.Memory)
+		if c.MemoryReservation != nil {
+			memory += fmt.Sprintf(" (reservation %d)", *c.MemoryReservation)
+		}
+		ports := []string{}
+		for _, p := range c.PortMappings {
+			port := fmt.Sprintf("%s/%s", utils.ShowInt(p.ContainerPort), showValue(string(p.Protocol)))
+			if p.HostPort != nil {
+				port += fmt.Sprintf(" -> %d", *p.HostPort)
+			}
+			ports = append(ports, port)
+		}
+		env := []string{}
+		for _, e := range c.Environment {
+			env = append(env, utils.ShowString(e.Name))
+		}
+		logDriver := ""
+		if c.LogConfiguration != nil {
+			logDriver = string(c.LogConfiguration.LogDriver)
+		}
+
+		fmt.Fprintf(w, "  %s:\n", utils.ShowString(c.Name))
+		fmt.Fprintf(w, "    Image:\t%s\n", utils.ShowString(c.Image))
+		fmt.Fprintf(w, "    Essential:\t%s\n", strconv.FormatBool(aws.ToBool(c.Essential)))
+		fmt.Fprintf(w, "    CPU/Memory:\t%d/%s\n", c.Cpu, memory)
+		fmt.Fprintf(w, "    Ports:\t%s\n", utils.ShowArray(ports))
+		fmt.Fprintf(w, "    Environment:\t%s\n", utils.ShowArray(env))
+		fmt.Fprintf(w, "    Log Driver:\t%s\n", showValue(logDriver))
+		// secret values are not shown, only where they come from
+		fmt.Fprintf(w, "    Secrets:\n")
+		if len(c.Secrets) == 0 {
+			fmt.Fprintf(w, "      %s\n", utils.EmptyText)
+		}
+		for _, s := range c.Secrets {
+			fmt.Fprintf(w, "      %s:\t%s\n", utils.ShowString(s.Name), utils.ShowString(s.ValueFrom))
+		}
+	}
+}
+
+// Fields of JSON value with readable labels, array items are labeled by their name and null fields are skipped
+func describeJSONNode(w *tabwriter.Writer, n *jsonNode, indent string) {
+	for i, c := range n.children {
+		if string(c.raw) == "null" {
+			continue
+		}
+		label := describeLabel(c.key)
+		if n.array {
+			label = fmt.Sprintf("#%d", i+1)
+			if name := c.child("Name"); name != nil && !name.object && !name.array {
+				label = name.text()
+			}
+		}
+		switch {
+		case (c.object || c.array) && len(c.children) == 0:
+			fmt.Fprintf(w, "%s%s:\t%s\n", indent, label, utils.EmptyText)
+		case c.array && !c.children[0].object && !c.children[0].array:
+			values := []string{}
+			for _, item := range c.children {
+				values = append(values, item.text())
+			}
+			fmt.Fprintf(w, "%s%s:\t%s\n", indent, label, strings.Join(values, ", "))
+		case c.object || c.array:
+			fmt.Fprintf(w, "%s%s:\n", indent, label)
+			describeJSONNode(w, c, indent+"  ")
+		default:
+			fmt.Fprintf(w, "%s%s:\t%s\n", indent, label, c.text())
+		}
+	}
+}
+
+// Words of PascalCase key, e.g. "ClusterArn" to "Cluster Arn" and "TaskARN" to "Task ARN"
+func describeLabel(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Name of target group ARN like "arn:aws:elasticloadbalancing:...:targetgroup/web-tg/6d0ecf831eec9f09"
+func targetGroupName(arn *string) string {
+	parts := strings.Split(aws.ToString(arn), "/")
+	if len(parts) < 3 {
+		return utils.ShowString(arn)
+	}
+	return parts[len(parts)-2]
+}
+
+func showValue(s string) string {
+	if s == "" {
+		return utils.EmptyText
+	}
+	return s
+}
+
+func showTimeAge(t *time.Time) string {
+	if t == nil {
+		return utils.EmptyText
+	}
+	return fmt.Sprintf("%s (%s)", utils.ShowTime(t), utils.Age(t))
+}
+
+func enabledText(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
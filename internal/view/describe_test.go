@@ -0,0 +1,254 @@
+package view
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/utils"
+)
+
+func TestJsonToYAML(t *testing.T) {
+	jsonBytes := []byte(`{
+  "Name": "web",
+  "Port": "8080",
+  "Count": 2,
+  "Tags": [],
+  "Env": [{"Name": "MODE", "Value": "a: b"}]
+}`)
+	got, err := jsonToYAML(jsonBytes)
+	if err != nil {
+		t.Fatalf("jsonToYAML: %v", err)
+	}
+	want := `Name: web
+Port: "8080"
+Count: 2
+Tags: []
+Env:
+  - Name: MODE
+    Value: 'a: b'
+`
+	if string(got) != want {
+		t.Errorf("Got:\n%s\nWant:\n%s", got, want)
+	}
+
+	back, err := utils.YAMLToJSON(got)
+	if err != nil {
+		t.Fatalf("YAMLToJSON: %v", err)
+	}
+	if compactJSON(t, back) != compactJSON(t, jsonBytes) {
+		t.Errorf("Got %s, Want: same JSON after round trip", back)
+	}
+}
+
+func TestDescribeLabel(t *testing.T) {
+	for key, want := range map[string]string{
+		"ClusterArn":        "Cluster Arn",
+		"TaskDefinitionARN": "Task Definition ARN",
+		"CPU":               "CPU",
+		"ipv6Address":       "ipv6 Address",
+		"name":              "name",
+	} {
+		if got := describeLabel(key); got != want {
+			t.Errorf("Got %q, Want %q", got, want)
+		}
+	}
+}
+
+func TestColorizeKeys(t *testing.T) {
+	theme.Blue = "blue"
+	defer func() { theme.Blue = "" }()
+	testCases := []struct {
+		line string
+		want string
+	}{
+		{"Task Definition:  web:4", "[blue::b]Task Definition[-:-:-]:  web:4"},
+		{"  - Name: MODE", "  - [blue::b]Name[-:-:-]: MODE"},
+		{"Deployments:", "[blue::b]Deployments[-:-:-]:"},
+		{"  5m ago  (service web) started 1 tasks: (task a)", "  5m ago  (service web) started 1 tasks: (task a)"},
+		{"  app  nginx  STOPPED  1  Error: pull failed", "  app  nginx  STOPPED  1  Error: pull failed"},
+		{"Image: [latest]", "[blue::b]Image[-:-:-]: [latest[]"},
+	}
+	for _, tc := range testCases {
+		if got := colorizeKeys(tc.line); got != tc.want {
+			t.Errorf("Got %q, Want %q", got, tc.want)
+		}
+	}
+}
+
+func TestDescribeText(t *testing.T) {
+	app, _ := newApp(Option{})
+	app.secondaryKind = DescriptionKind
+	v := newView(app, []keyDescriptionPair{}, secondaryPageKeyMap{})
+	created := time.Now().Add(-time.Hour)
+
+	service := &types.Service{
+		ServiceName:    aws.String("web"),
+		ClusterArn:     aws.String("arn:aws:ecs:us-east-1:111111:cluster/prod"),
+		Status:         aws.String("ACTIVE"),
+		TaskDefinition: aws.String("arn:aws:ecs:us-east-1:111111:task-definition/web:4"),
+		LaunchType:     types.LaunchTypeFargate,
+		DesiredCount:   2,
+		RunningCount:   2,
+		CreatedAt:      &created,
+		Deployments: []types.Deployment{
+			{Id: aws.String("ecs-svc/1"), Status: aws.String("PRIMARY"), RolloutState: types.DeploymentRolloutStateCompleted, DesiredCount: 2, RunningCount: 2, TaskDefinition: aws.String("arn:aws:ecs:us-east-1:111111:task-definition/web:4"), UpdatedAt: &created},
+		},
+		LoadBalancers: []types.LoadBalancer{
+			{TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:111111:targetgroup/web-tg/abc"), ContainerName: aws.String("app"), ContainerPort: aws.Int32(8080)},
+		},
+		NetworkConfiguration: &types.NetworkConfiguration{AwsvpcConfiguration: &types.AwsVpcConfiguration{
+			Subnets:        []string{"subnet-a", "subnet-b"},
+			AssignPublicIp: types.AssignPublicIpDisabled,
+		}},
+	}
+	for i := 0; i < 7; i++ {
+		service.Events = append(service.Events, types.ServiceEvent{CreatedAt: &created, Message: aws.String("(service web) has reached a steady state.")})
+	}
+	task := &types.Task{
+		TaskArn:           aws.String("arn:aws:ecs:us-east-1:111111:task/prod/0a1b"),
+		LastStatus:        aws.String("STOPPED"),
+		DesiredStatus:     aws.String("STOPPED"),
+		TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:111111:task-definition/web:4"),
+		StoppedReason:     aws.String("Essential container in task exited"),
+		Containers: []types.Container{
+			{Name: aws.String("app"), Image: aws.String("nginx:latest"), LastStatus: aws.String("STOPPED"), ExitCode: aws.Int32(137)},
+		},
+		Attachments: []types.Attachment{
+			{Type: aws.String("ElasticNetworkInterface"), Status: aws.String("DELETED"), Id: aws.String("att-1"), Details: []types.KeyValuePair{{Name: aws.String("privateIPv4Address"), Value: aws.String("10.0.1.5")}}},
+		},
+	}
+	taskDefinition := &types.TaskDefinition{
+		Family:   aws.String("web"),
+		Revision: 4,
+		ContainerDefinitions: []types.ContainerDefinition{
+			{
+				Name:         aws.String("app"),
+				Image:        aws.String("nginx:latest"),
+				Essential:    aws.Bool(true),
+				PortMappings: []types.PortMapping{{ContainerPort: aws.Int32(8080), HostPort: aws.Int32(8080), Protocol: types.TransportProtocolTcp}},
+				Environment:  []types.KeyValuePair{{Name: aws.String("MODE"), Value: aws.String("production")}},
+				Secrets:      []types.Secret{{Name: aws.String("DB_PASSWORD"), ValueFrom: aws.String("arn:aws:ssm:us-east-1:111111:parameter/db")}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		kind    kind
+		entity  Entity
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "service",
+			kind:   ServiceKind,
+			entity: Entity{service: service},
+			want: []string{
+				"Task Definition:  web:4",
+				"Tasks:            2 desired, 2 running, 0 pending",
+				"  ecs-svc/1  PRIMARY  COMPLETED",
+				"  web-tg        <empty>        app        8080",
+				"  Subnets:          subnet-a,subnet-b",
+				"  (2 more, press w to show service events)",
+			},
+		},
+		{
+			name:   "task",
+			kind:   TaskKind,
+			entity: Entity{task: task},
+			want: []string{
+				"Status:             STOPPED (desired STOPPED)",
+				"Stopped Reason:     Essential container in task exited",
+				"  app   nginx:latest  STOPPED  <empty>  137        <empty>",
+				"  ElasticNetworkInterface:  DELETED (att-1)",
+				"    privateIPv4Address:     10.0.1.5",
+			},
+		},
+		{
+			name:   "task definition",
+			kind:   TaskDefinitionKind,
+			entity: Entity{taskDefinition: taskDefinition},
+			want: []string{
+				"Family:           web:4",
+				"    Ports:        8080/tcp -> 8080",
+				"    Environment:  MODE",
+				"      DB_PASSWORD:  arn:aws:ssm:us-east-1:111111:parameter/db",
+			},
+			notWant: []string{"production"},
+		},
+		{
+			name:   "other kinds list JSON fields",
+			kind:   ClusterKind,
+			entity: Entity{cluster: &types.Cluster{ClusterName: aws.String("prod"), ActiveServicesCount: 3, CapacityProviders: []string{"FARGATE", "FARGATE_SPOT"}}},
+			want: []string{
+				"Cluster Name:                          prod",
+				"Active Services Count:                 3",
+				"Capacity Providers:                    FARGATE, FARGATE_SPOT",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app.kind = tc.kind
+			_, jsonBytes, err := v.getJsonString(tc.entity)
+			if err != nil {
+				t.Fatalf("getJsonString: %v", err)
+			}
+			got, err := v.describeText(tc.entity, jsonBytes)
+			if err != nil {
+				t.Fatalf("describeText: %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Want %q in:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tc.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Got %q in:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSwitchDescribeFormat(t *testing.T) {
+	app, _ := newApp(Option{})
+	app.kind = ClusterKind
+	app.secondaryKind = DescriptionKind
+	cluster := types.Cluster{ClusterName: aws.String(clusterName1)}
+	v := newView(app, []keyDescriptionPair{}, secondaryPageKeyMap{})
+	entity := Entity{cluster: &cluster, entityName: clusterName1}
+
+	content := func() string {
+		_, item := v.tablePages.GetFrontPage()
+		return item.(*textPage).content
+	}
+	v.showJsonPages(entity)
+	if !json.Valid([]byte(content())) {
+		t.Errorf("Want: JSON by default, Got:\n%s", content())
+	}
+
+	v.switchDescribeFormat(entity)
+	if !strings.Contains(content(), "ClusterName[-:-:-]: "+clusterName1) {
+		t.Errorf("Want: YAML, Got:\n%s", content())
+	}
+	_, raw, _ := v.getDescribeString(entity)
+	if _, err := utils.YAMLToJSON(raw); err != nil {
+		t.Errorf("Want: YAML content, err: %v", err)
+	}
+
+	v.switchDescribeFormat(entity)
+	if !strings.Contains(content(), "Cluster Name[-:-:-]:") {
+		t.Errorf("Want: describe text, Got:\n%s", content())
+	}
+
+	v.switchDescribeFormat(entity)
+	if app.describeFormat != describeJSON {
+		t.Errorf("Got format %d, Want: back to JSON", app.describeFormat)
+	}
+}
@@ -70,7 +70,7 @@ func (v *view) catFile() (*tview.Form, *string) {
 			fmt.Sprintf("cat %s", path),
 		}
 		bin, _ := exec.LookPath(awsCli)
-		cmd := exec.Command(bin, args...)
+		cmd := exec.Command(bin, v.app.cliArgs(args)...)
 
 		go func() {
 			v.app.Notice.Info("Working in progress")
@@ -312,7 +312,7 @@ func (v *view) cpForm() (*tview.Form, *string) {
 		v.app.Suspend(func() {
 			v.app.isSuspended = true
 			slog.Info("exec", "command", bin+" "+strings.Join(uploadArgs, " "))
-			uploadCmd := exec.Command(bin, uploadArgs...)
+			uploadCmd := exec.Command(bin, v.app.cliArgs(uploadArgs)...)
 			uploadCmd.Stdin, uploadCmd.Stdout = os.Stdin, os.Stdout
 			uploadCmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 			uploadCmd.Stdout.Write([]byte("\nUpload...\n"))
@@ -324,7 +324,7 @@ func (v *view) cpForm() (*tview.Form, *string) {
 			time.Sleep(time.Second)
 
 			slog.Info("exec", "command", bin+" "+strings.Join(downloadArgs, " "))
-			downloadCmd := exec.Command(bin, downloadArgs...)
+			downloadCmd := exec.Command(bin, v.app.cliArgs(downloadArgs)...)
 			downloadCmd.Stdin, downloadCmd.Stdout = os.Stdin, os.Stdout
 			downloadCmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 			downloadCmd.Stdout.Write([]byte("\nDownload...\n"))
@@ -337,7 +337,7 @@ func (v *view) cpForm() (*tview.Form, *string) {
 
 			if delete {
 				slog.Info("exec", "command", bin+" "+strings.Join(deleteArgs, " "))
-				deleteCmd := exec.Command(bin, deleteArgs...)
+				deleteCmd := exec.Command(bin, v.app.cliArgs(deleteArgs)...)
 				deleteCmd.Stdin, deleteCmd.Stdout = os.Stdin, os.Stdout
 				deleteCmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 				deleteCmd.Stdout.Write([]byte("\nDelete...\n"))
@@ -36,7 +36,7 @@ func (v *view) initFilterInput() {
 		}
 		v.saveCurrentViewState()
 		v.filterApplyTimer = time.AfterFunc(1*time.Second, func() {
-			v.app.QueueUpdateDraw(func() {
+			v.app.dispatch(func() {
 				if v.filterActive {
 					v.applyFilter()
 				}
@@ -65,7 +65,7 @@ func (v *view) updateFilterTitle() {
 	}
 	filterText := v.filterInput.GetText()
 	count := v.table.GetRowCount() - 1 // -1 for headers
-	currentTitle := fmt.Sprintf(color.TableTitleFmt, v.app.kind, "all", count)
+	currentTitle := fmt.Sprintf(color.TableTitleFmt, v.app.kind, "all", count) + v.titleNote
 	if len(filterText) > 0 {
 		filterLabel := fmt.Sprintf("[black:blue]</%s>[-:-]", filterText)
 		currentTitle = currentTitle + " " + filterLabel
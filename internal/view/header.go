@@ -13,45 +13,59 @@ const (
 )
 
 var hotKeyMap = map[string]keyDescriptionPair{
-	"/":      {key: "/", description: "Filter (ESC to clear)"},
-	"f1~f12": {key: "f1~f12", description: "Sort by column"},
-	"a":      {key: "a", description: "Show service auto scaling"},
-	"c":      {key: "c", description: "Copy content to clipboard"},
-	"f":      {key: "f", description: "Toggle full screen"},
-	"h":      {key: "h, left arrow", description: "Back"},
-	"l":      {key: "l, right arrow", description: "Select"},
-	"L":      {key: "shift-l", description: "Show cloudwatch logs(Only support awslogs logDriver)"},
-	"m":      {key: "m", description: "Show metrics(CPU/Memory)"},
-	"r":      {key: "r", description: "Refresh"},
-	"R":      {key: "shift-r", description: "Rollback service deployment"},
-	"t":      {key: "t", description: "Show task definitions"},
-	"p":      {key: "p", description: "Show service deployments"},
-	"n":      {key: "n", description: "Show related EC2 instances"},
-	"N":      {key: "shift-n", description: "Show all cluster tasks"},
-	"s":      {key: "s", description: "Shell access"},
-	"x":      {key: "x", description: "Toggle running/stopped tasks"},
-	"w":      {key: "w", description: "Show service events"},
-	"v":      {key: "v", description: "Show service revision"},
-	"S":      {key: "shift-s", description: "Stop task"},
-	"P":      {key: "shift-p", description: "Transfer file though a S3 bucket"},
-	"D":      {key: "shift-d", description: "Download text file content(beta)"},
-	"F":      {key: "shift-f", description: "Start port forwarding session"},
-	"T":      {key: "shift-t", description: "Terminate port forwarding session"},
-	"U":      {key: "shift-u", description: "Update service"},
-	"E":      {key: "shift-e", description: "Exec command"},
-	"ctrlD":  {key: "ctrl-d", description: "Exit from container"},
-
-	"enter": {key: "enter", description: "Select"},
-	"esc":   {key: "esc", description: "Back"},
-	"ctrlZ": {key: "ctrl-z", description: "Back"},
-	"ctrlC": {key: "ctrl-c", description: "Exit"},
-	"ctrlR": {key: "ctrl-r", description: "Show AWS regions"},
-	"ctrlP": {key: "ctrl-p", description: "Show AWS profiles"},
-	"ctrlL": {key: "ctrl-l", description: "Realtime log streaming(Only support one log group)"},
-	"?":     {key: "?", description: "Help"},
-	"b":     {key: "b", description: "Open in browser"},
-	"d":     {key: "d", description: "Describe"},
-	"e":     {key: "e", description: "Open in default editor"},
+	"/":        {key: "/", description: "Filter (ESC to clear)"},
+	"search":   {key: "/", description: "Search (ESC to clear)"},
+	"nN":       {key: "n, shift-n", description: "Next/previous match"},
+	"jsonTree": {key: "shift-t", description: "Toggle JSON tree"},
+	"format":   {key: "y", description: "Switch JSON/YAML/describe"},
+	"f1~f12":   {key: "f1~f12", description: "Sort by column"},
+	"a":        {key: "a", description: "Show service auto scaling"},
+	"c":        {key: "c", description: "Copy content to clipboard"},
+	"f":        {key: "f", description: "Toggle full screen"},
+	"h":        {key: "h, left arrow", description: "Back"},
+	"l":        {key: "l, right arrow", description: "Select"},
+	"L":        {key: "shift-l", description: "Show cloudwatch logs(Only support awslogs logDriver)"},
+	"m":        {key: "m", description: "Show metrics(CPU/Memory/Tasks)"},
+	"1~9":      {key: "1~9", description: "Toggle container in merged logs"},
+	"1~4":      {key: "1~4", description: "Select metrics window(1h/6h/24h/7d)"},
+	"r":        {key: "r", description: "Refresh"},
+	"R":        {key: "shift-r", description: "Rollback service deployment"},
+	"t":        {key: "t", description: "Show task definitions"},
+	"p":        {key: "p", description: "Show service deployments"},
+	"n":        {key: "n", description: "Show related EC2 instances"},
+	"N":        {key: "shift-n", description: "Show all cluster tasks"},
+	"s":        {key: "s", description: "Shell access"},
+	"x":        {key: "x", description: "Toggle running/stopped tasks"},
+	"w":        {key: "w", description: "Show service events"},
+	"v":        {key: "v", description: "Show service revision"},
+	"S":        {key: "shift-s", description: "Stop task"},
+	"P":        {key: "shift-p", description: "Transfer file though a S3 bucket"},
+	"D":        {key: "shift-d", description: "Download text file content(beta)"},
+	"F":        {key: "shift-f", description: "Start port forwarding session"},
+	"T":        {key: "shift-t", description: "Terminate port forwarding session"},
+	"U":        {key: "shift-u", description: "Update service"},
+	"E":        {key: "shift-e", description: "Exec command"},
+	"ctrlD":    {key: "ctrl-d", description: "Exit from container"},
+	"space":    {key: "space", description: "Mark to list clusters together"},
+
+	"enter":       {key: "enter", description: "Select"},
+	"esc":         {key: "esc", description: "Back"},
+	"ctrlZ":       {key: "ctrl-z", description: "Back"},
+	"ctrlC":       {key: "ctrl-c", description: "Exit"},
+	"ctrlR":       {key: "ctrl-r", description: "Show AWS regions"},
+	"ctrlP":       {key: "ctrl-p", description: "Show AWS profiles"},
+	"ctrlL":       {key: "ctrl-l", description: "Live tail logs"},
+	"tailPause":   {key: "p", description: "Pause/resume live tail"},
+	"tailStop":    {key: "x", description: "Stop live tail"},
+	"i":           {key: "i", description: "Query with Logs Insights"},
+	"o":           {key: "o", description: "Load older logs"},
+	"logRange":    {key: "t", description: "Select time range and log stream"},
+	"enterRecord": {key: "enter", description: "Show log record"},
+	"ctrlRQuery":  {key: "ctrl-r", description: "Run query again"},
+	"?":           {key: "?", description: "Help"},
+	"b":           {key: "b", description: "Open in browser"},
+	"d":           {key: "d", description: "Describe"},
+	"e":           {key: "e", description: "Open in default editor"},
 
 	"j":       {key: "j, down arrow", description: "Down"},
 	"k":       {key: "k, up arrow", description: "Up"},
@@ -91,6 +105,10 @@ var basicKeyInputs = append(tableInputs, []keyDescriptionPair{
 type secondaryPageKeyMap = map[kind][]keyDescriptionPair
 
 var describePageKeys = []keyDescriptionPair{
+	hotKeyMap["format"],
+	hotKeyMap["jsonTree"],
+	hotKeyMap["search"],
+	hotKeyMap["nN"],
 	hotKeyMap["f"],
 	hotKeyMap["b"],
 	hotKeyMap["c"],
@@ -99,20 +117,54 @@ var describePageKeys = []keyDescriptionPair{
 }
 
 var otherDescribePageKeys = []keyDescriptionPair{
+	hotKeyMap["search"],
+	hotKeyMap["nN"],
 	hotKeyMap["f"],
 	hotKeyMap["b"],
 	hotKeyMap["ctrlZ"],
 }
 
+var metricsPageKeys = []keyDescriptionPair{
+	hotKeyMap["search"],
+	hotKeyMap["nN"],
+	hotKeyMap["1~4"],
+	hotKeyMap["f"],
+	hotKeyMap["c"],
+	hotKeyMap["ctrlZ"],
+}
+
+var logTailPageKeys = []keyDescriptionPair{
+	hotKeyMap["tailPause"],
+	hotKeyMap["tailStop"],
+	hotKeyMap["c"],
+	hotKeyMap["esc"],
+}
+
+var logsInsightsPageKeys = []keyDescriptionPair{
+	hotKeyMap["i"],
+	hotKeyMap["f1~f12"],
+	hotKeyMap["enterRecord"],
+	hotKeyMap["ctrlRQuery"],
+	hotKeyMap["c"],
+	hotKeyMap["esc"],
+}
+
 var logPageKeys = []keyDescriptionPair{
+	hotKeyMap["search"],
+	hotKeyMap["nN"],
 	hotKeyMap["f"],
 	hotKeyMap["e"],
 	hotKeyMap["b"],
+	hotKeyMap["o"],
+	hotKeyMap["logRange"],
+	hotKeyMap["i"],
 	hotKeyMap["ctrlL"],
 	hotKeyMap["ctrlR"],
 	hotKeyMap["ctrlZ"],
 }
 
+var taskLogPageKeys = append([]keyDescriptionPair{hotKeyMap["1~9"]}, logPageKeys...)
+
 // Build header flex show on top of view, will change when selection change
 func (v *view) buildHeaderFlex(title string, items []headerItem, keys []keyDescriptionPair) *tview.Flex {
 	headerFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
@@ -3,6 +3,7 @@ package view
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/keidarcy/e1s/internal/color"
 	"github.com/keidarcy/e1s/internal/ui"
@@ -54,6 +55,8 @@ func (app *App) showHelpPage() {
 		{key: "config-file", description: app.Option.ConfigFile},
 		{key: "shell", description: app.Option.Shell},
 		{key: "refresh", description: strconv.Itoa(app.Option.Refresh)},
+		{key: "api-timeout", description: strconv.Itoa(app.Option.Timeout)},
+		{key: "regions", description: strings.Join(globalRegions, ",")},
 		{key: "theme", description: app.Option.Theme},
 		{key: "cluster", description: app.Option.Cluster},
 	})
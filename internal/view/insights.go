@@ -0,0 +1,383 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/keidarcy/e1s/internal/api"
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/keidarcy/e1s/internal/ui"
+	"github.com/keidarcy/e1s/internal/utils"
+	"github.com/rivo/tview"
+)
+
+const (
+	logsInsightsDefaultQuery = "fields @timestamp, @logStream, @message\n%s| sort @timestamp desc\n| limit 100"
+	logsInsightsFilterFmt    = "| filter @logStream like /%s/\n"
+	// index of logsInsightsRanges used by a new query
+	logsInsightsDefaultRange = 1
+)
+
+// Relative time ranges of Logs Insights queries
+var logsInsightsRanges = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"15m", 15 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// Logs Insights query of a resource and its last results, kept to edit and run again
+type logsInsights struct {
+	// entity name of the queried resource
+	key        string
+	groups     []string
+	query      string
+	rangeIndex int
+	result     *api.LogsQueryResult
+	// bumped on every run so results of a replaced run are dropped
+	run int
+	// cancel running query, nil when idle
+	cancel     context.CancelFunc
+	table      *tview.Table
+	sortColumn int
+	sortOrder  string
+}
+
+// Task definition, task ID and container name of entity logs, nil task definition when entity has no logs
+func (v *view) logScope(entity Entity) (tdArn *string, taskId, containerName string) {
+	switch {
+	case entity.service != nil:
+		tdArn = entity.service.TaskDefinition
+	case entity.task != nil:
+		tdArn = entity.task.TaskDefinitionArn
+		taskId = utils.ArnToName(entity.task.TaskArn)
+	case entity.container != nil && v.app.task != nil:
+		tdArn = v.app.task.TaskDefinitionArn
+		taskId = utils.ArnToName(v.app.task.TaskArn)
+		containerName = *entity.container.Name
+	}
+	return tdArn, taskId, containerName
+}
+
+// Use query of entity log groups, or the last query when it is the same entity.
+// Log groups of a new query load in background, use runs once they are loaded.
+func (v *view) withLogsInsights(entity Entity, use func(q *logsInsights)) {
+	if q := v.app.insights; q != nil && q.key == entity.entityName {
+		use(q)
+		return
+	}
+	_, taskId, containerName := v.logScope(entity)
+	v.loadLogTargets(entity, "query", func(targets []api.LogTarget) {
+		groups := []string{}
+		for _, target := range targets {
+			groups = append(groups, target.Group)
+		}
+		// scope task and container queries by log stream name "${prefix}/${container}/${taskId}"
+		filter := ""
+		switch {
+		case containerName != "":
+			filter = fmt.Sprintf(logsInsightsFilterFmt, containerName+`\/`+taskId)
+		case taskId != "":
+			filter = fmt.Sprintf(logsInsightsFilterFmt, taskId)
+		}
+
+		v.app.stopLogsInsights()
+		v.app.insights = &logsInsights{
+			key:        entity.entityName,
+			groups:     groups,
+			query:      fmt.Sprintf(logsInsightsDefaultQuery, filter),
+			rangeIndex: logsInsightsDefaultRange,
+			sortColumn: -1,
+			sortOrder:  "desc",
+		}
+		use(v.app.insights)
+	})
+}
+
+// Show query form of entity logs, returnTo gets focus when the form is closed
+func (v *view) showLogsInsightsForm(entity Entity, returnTo tview.Primitive) {
+	v.withLogsInsights(entity, func(q *logsInsights) {
+		v.showLogsInsightsQueryForm(entity, q, returnTo)
+	})
+}
+
+func (v *view) showLogsInsightsQueryForm(entity Entity, q *logsInsights, returnTo tview.Primitive) {
+	title := fmt.Sprintf(" Logs Insights query of [%s::b]%s[-:-:-] ", theme.Magenta, entity.entityName)
+	f := ui.StyledForm(title)
+	groupsLabel := "Log groups"
+	rangeLabel := "Time range"
+	queryLabel := "Query"
+	ranges := []string{}
+	for _, r := range logsInsightsRanges {
+		ranges = append(ranges, "last "+r.name)
+	}
+	f.AddInputField(groupsLabel, strings.Join(q.groups, ","), 80, nil, nil)
+	f.AddDropDown(rangeLabel, ranges, q.rangeIndex, nil)
+	f.AddTextArea(queryLabel, q.query, 80, 6, 0, nil)
+
+	closeForm := func() {
+		v.handleFullScreenContentDone()
+		v.app.SetFocus(returnTo)
+	}
+	f.AddButton("Cancel", closeForm)
+	f.AddButton("Run", func() {
+		groups := []string{}
+		for _, g := range strings.Split(f.GetFormItemByLabel(groupsLabel).(*tview.InputField).GetText(), ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				groups = append(groups, g)
+			}
+		}
+		query := strings.TrimSpace(f.GetFormItemByLabel(queryLabel).(*tview.TextArea).GetText())
+		if len(groups) == 0 || query == "" {
+			v.app.Notice.Warn("log groups and query are required")
+			return
+		}
+		q.groups = groups
+		q.query = query
+		q.rangeIndex, _ = f.GetFormItemByLabel(rangeLabel).(*tview.DropDown).GetCurrentOption()
+		v.handleFullScreenContentDone()
+		v.runLogsInsights(entity)
+	})
+
+	v.app.Pages.AddPage(title, ui.Modal(f, 100, 17, 0, closeForm), true, true)
+}
+
+// Run query of entity and show its results table
+func (v *view) runLogsInsights(entity Entity) {
+	v.withLogsInsights(entity, func(q *logsInsights) {
+		v.runLogsInsightsQuery(entity, q)
+	})
+}
+
+func (v *view) runLogsInsightsQuery(entity Entity, q *logsInsights) {
+	if q.cancel != nil {
+		q.cancel()
+	}
+	v.app.secondaryKind = LogsInsightsKind
+
+	r := logsInsightsRanges[q.rangeIndex]
+	end := time.Now()
+	input := api.LogsQuery{
+		LogGroups: q.groups,
+		Query:     q.query,
+		Start:     end.Add(-r.duration),
+		End:       end,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+	q.result = nil
+	q.run++
+	run := q.run
+	v.showLogsInsights(entity)
+	v.app.Notice.Infof("Running Logs Insights query of last %s...", r.name)
+
+	store := v.app.Store
+	fetch := func() (*api.LogsQueryResult, error) {
+		defer cancel()
+		return store.QueryLogs(ctx, input)
+	}
+	done := func(result *api.LogsQueryResult, err error) {
+		if v.app.insights != q || q.run != run || errors.Is(err, context.Canceled) {
+			return
+		}
+		q.cancel = nil
+		if err != nil {
+			v.app.Notice.Warnf("failed to run Logs Insights query, err: %v", err)
+			result = &api.LogsQueryResult{}
+		} else {
+			v.app.Notice.Info(logsInsightsMatched(result))
+		}
+		q.result = result
+		if q.sortColumn >= len(result.Fields) {
+			q.sortColumn = -1
+		}
+		q.render()
+	}
+	if !v.app.running {
+		done(fetch())
+		return
+	}
+	go func() {
+		result, err := fetch()
+		v.app.dispatch(func() {
+			done(result, err)
+		})
+	}()
+}
+
+// Records matched by a query, results are capped by the query limit
+func logsInsightsMatched(result *api.LogsQueryResult) string {
+	matched := int(result.RecordsMatched)
+	if matched > len(result.Rows) {
+		return fmt.Sprintf("Logs Insights query matched %d records, first %d shown", matched, len(result.Rows))
+	}
+	return fmt.Sprintf("Logs Insights query matched %d records", matched)
+}
+
+// Run Logs Insights query of selected resource again
+func (v *view) switchToLogsInsights() {
+	selected, err := v.getCurrentSelection()
+	if err != nil {
+		v.app.Notice.Warnf("failed to switchToLogsInsights")
+		return
+	}
+	v.runLogsInsights(selected)
+}
+
+// Show results table of query in table area
+func (v *view) showLogsInsights(entity Entity) {
+	q := v.app.insights
+	q.table = tview.NewTable().SetFixed(1, 0).SetSelectable(true, false)
+	q.table.SetBorder(true).
+		SetTitle(fmt.Sprintf(color.TableSecondaryTitleFmt, v.app.kind, entity.entityName, LogsInsightsKind)).
+		SetBorderPadding(0, 0, 1, 1)
+	q.render()
+
+	pageName := v.app.kind.getSecondaryPageName(entity.entityName + "." + LogsInsightsKind.String())
+	q.table.SetSelectedFunc(func(row, _ int) {
+		v.showLogsInsightsRecord(entity, pageName, row)
+	})
+	q.table.SetDoneFunc(func(key tcell.Key) {
+		v.app.stopLogsInsights()
+		v.handleTableContentDone(key)
+	})
+	q.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'i':
+			v.showLogsInsightsForm(entity, q.table)
+			return nil
+		case 'c':
+			v.app.copyToClipboard("query results", q.tsv())
+			return nil
+		}
+		switch key := event.Key(); {
+		case key >= tcell.KeyF1 && key <= tcell.KeyF12:
+			q.sortByColumn(int(key - tcell.KeyF1))
+			return nil
+		case key == tcell.KeyCtrlR:
+			v.runLogsInsights(entity)
+			return nil
+		case key == tcell.KeyCtrlZ:
+			v.app.stopLogsInsights()
+			v.handleTableContentDone(0)
+			return nil
+		}
+		return event
+	})
+
+	v.tablePages.AddPage(pageName, q.table, true, true)
+	v.handleHeaderPageSwitch(entity)
+}
+
+// Show all fields of a result row
+func (v *view) showLogsInsightsRecord(entity Entity, resultsPage string, row int) {
+	q := v.app.insights
+	values, ok := q.table.GetCell(row, 0).GetReference().([]string)
+	if !ok {
+		return
+	}
+	var b strings.Builder
+	for i, field := range q.result.Fields {
+		b.WriteString(fmt.Sprintf(color.HeaderItemFmt+"\n", field, tview.Escape(values[i])))
+	}
+	text := getSecondaryTextItem(b.String(), fmt.Sprintf(color.TableSecondaryTitleFmt, v.app.kind, entity.entityName, "log record"))
+	text.SetDoneFunc(func(tcell.Key) {
+		v.tablePages.SwitchToPage(resultsPage)
+	})
+	v.tablePages.AddPage(resultsPage+".record", text, true, true)
+}
+
+// Cancel running query, the query is kept to run again
+func (app *App) stopLogsInsights() {
+	if app.insights == nil || app.insights.cancel == nil {
+		return
+	}
+	app.insights.cancel()
+	app.insights.cancel = nil
+}
+
+// Render results in sort order, first cell of each row references its values
+func (q *logsInsights) render() {
+	q.table.Clear()
+	if q.result == nil {
+		q.table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf(color.GrayFmt, "Running query...")).SetSelectable(false))
+		return
+	}
+	if len(q.result.Fields) == 0 {
+		q.table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf(color.GrayFmt, "No results")).SetSelectable(false))
+		return
+	}
+
+	for x, field := range q.result.Fields {
+		if x == q.sortColumn {
+			if q.sortOrder == "asc" {
+				field += " ↑"
+			} else {
+				field += " ↓"
+			}
+		}
+		q.table.SetCell(0, x, tview.NewTableCell(field).SetTextColor(color.Color(theme.Yellow)).SetSelectable(false))
+	}
+
+	rows := slices.Clone(q.result.Rows)
+	if q.sortColumn >= 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return CompareCellValues(rows[i][q.sortColumn], rows[j][q.sortColumn], q.result.Fields[q.sortColumn], false, q.sortOrder)
+		})
+	}
+	last := len(q.result.Fields) - 1
+	for y, row := range rows {
+		for x, value := range row {
+			cell := tview.NewTableCell(tview.Escape(value))
+			// last column takes the rest of the line, usually @message
+			if x == last {
+				cell.SetExpansion(1)
+			}
+			if x == 0 {
+				cell.SetReference(row)
+			}
+			q.table.SetCell(y+1, x, cell)
+		}
+	}
+}
+
+// Sort results by column, same column toggles order
+func (q *logsInsights) sortByColumn(column int) {
+	if q.result == nil || column >= len(q.result.Fields) {
+		return
+	}
+	if q.sortColumn == column {
+		if q.sortOrder == "asc" {
+			q.sortOrder = "desc"
+		} else {
+			q.sortOrder = "asc"
+		}
+	} else {
+		q.sortColumn = column
+		q.sortOrder = "desc"
+	}
+	q.render()
+}
+
+// Results as tab separated values with a header line
+func (q *logsInsights) tsv() string {
+	if q.result == nil {
+		return ""
+	}
+	lines := []string{strings.Join(q.result.Fields, "\t")}
+	for _, row := range q.result.Rows {
+		lines = append(lines, strings.Join(row, "\t"))
+	}
+	return strings.Join(lines, "\n")
+}
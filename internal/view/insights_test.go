@@ -0,0 +1,75 @@
+package view
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
+)
+
+func TestLogsInsights(t *testing.T) {
+	app := demoApp(t, Option{})
+	app.cluster = &types.Cluster{ClusterName: aws.String("demo-production")}
+	app.service = &types.Service{ServiceName: aws.String("web")}
+	app.kind = TaskKind
+	task := types.Task{
+		TaskArn:           aws.String("arn:aws:ecs:us-east-1:123456789012:task/demo-production/0a1b2c3d4e5f60718293a4b5c6d7e8f9"),
+		TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+	}
+	v := newTaskView([]types.Task{task}, false, app)
+	entity := Entity{task: &task, entityName: *task.TaskArn}
+
+	var q *logsInsights
+	v.withLogsInsights(entity, func(got *logsInsights) { q = got })
+	if q == nil || strings.Join(q.groups, ",") != "/ecs/demo/web" {
+		t.Fatalf("Got %+v, Want: query of /ecs/demo/web", q)
+	}
+	if !strings.Contains(q.query, "filter @logStream like /0a1b2c3d4e5f60718293a4b5c6d7e8f9/") {
+		t.Errorf("Got query %q, Want: filtered by task ID", q.query)
+	}
+
+	q.rangeIndex = len(logsInsightsRanges) - 1
+	v.runLogsInsights(entity)
+	if app.secondaryKind != LogsInsightsKind || q.result == nil {
+		t.Fatal("Want: query results shown")
+	}
+	if strings.Join(q.result.Fields, ",") != "@timestamp,@logStream,@message" {
+		t.Errorf("Got fields %v, Want: @timestamp,@logStream,@message", q.result.Fields)
+	}
+	// web and envoy containers of the task, latest first
+	if len(q.result.Rows) != 6 || q.result.Rows[0][0] < q.result.Rows[5][0] {
+		t.Fatalf("Got %d rows, Want: 6 rows of the task latest first", len(q.result.Rows))
+	}
+	for _, row := range q.result.Rows {
+		if !strings.HasSuffix(row[1], "/0a1b2c3d4e5f60718293a4b5c6d7e8f9") {
+			t.Errorf("Got log stream %s, Want: stream of the task", row[1])
+		}
+	}
+
+	q.sortByColumn(1)
+	q.sortByColumn(1)
+	if first := q.table.GetCell(1, 1).Text; !strings.HasPrefix(first, "ecs/envoy/") {
+		t.Errorf("Got %s first, Want: log streams in ascending order", first)
+	}
+	if header := q.table.GetCell(0, 1).Text; header != "@logStream ↑" {
+		t.Errorf("Got header %q, Want: sort marker", header)
+	}
+
+	var again *logsInsights
+	v.withLogsInsights(entity, func(got *logsInsights) { again = got })
+	if again != q {
+		t.Error("Want: edited query kept for the same task")
+	}
+}
+
+func TestLogsInsightsMatched(t *testing.T) {
+	rows := [][]string{{"a"}, {"b"}}
+	if got := logsInsightsMatched(&api.LogsQueryResult{Rows: rows, RecordsMatched: 250}); got != "Logs Insights query matched 250 records, first 2 shown" {
+		t.Errorf("Got %q, Want: matched records of a truncated result", got)
+	}
+	if got := logsInsightsMatched(&api.LogsQueryResult{Rows: rows, RecordsMatched: 2}); got != "Logs Insights query matched 2 records" {
+		t.Errorf("Got %q, Want: all matched records shown", got)
+	}
+}
@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
@@ -34,12 +35,16 @@ func (app *App) showInstancesPage(reload bool) error {
 		return nil
 	}
 
-	resources, err := app.Store.ListContainerInstances(app.cluster.ClusterName)
-
-	err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
-		return newInstanceView(resources, app)
+	var resources []types.ContainerInstance
+	store, clusterName := app.Store, app.cluster.ClusterName
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		resources, err = store.ListContainerInstances(ctx, clusterName)
+		return err
+	}, func(err error) error {
+		return buildResourcePage(resources, app, err, func() resourceViewBuilder {
+			return newInstanceView(resources, app)
+		})
 	})
-	return err
 }
 
 func (v *instanceView) getViewAndFooter() (*view, *tview.TextView) {
@@ -2,6 +2,7 @@ package view
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -11,7 +12,9 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/gdamore/tcell/v2"
+	"github.com/keidarcy/e1s/internal/api"
 	"github.com/keidarcy/e1s/internal/utils"
 )
 
@@ -58,13 +61,20 @@ func (v *view) switchToAutoScalingJson() {
 	}
 
 	serviceFullName := utils.ArnToFullName(serviceArn)
-	autoScaling, err := v.app.Store.GetAutoscaling(&serviceFullName)
-
-	if err != nil {
-		return
-	}
-	entity := Entity{autoScaling: autoScaling, entityName: *serviceArn}
-	v.showJsonPages(entity)
+	store := v.app.Store
+	var autoScaling *api.AutoScalingData
+	v.loadSecondaryContent(Entity{entityName: *serviceArn}, func(ctx context.Context) (err error) {
+		autoScaling, err = store.GetAutoscaling(ctx, &serviceFullName)
+		return err
+	}, func(err error) error {
+		if err != nil {
+			v.app.Notice.Warnf("failed to get auto scaling, err: %v", err)
+			return err
+		}
+		entity := Entity{autoScaling: autoScaling, entityName: *serviceArn}
+		v.showJsonPages(entity)
+		return nil
+	})
 }
 
 // Switch to service revision
@@ -79,27 +89,47 @@ func (v *view) switchToServiceRevisionJson() {
 		return
 	}
 
-	serviceRevision, err := v.app.Store.GetServiceRevision(serviceRevisionArn)
-
-	if err != nil {
-		return
-	}
-	entity := Entity{serviceRevision: serviceRevision, entityName: *selected.serviceDeployment.ServiceDeploymentArn}
-	v.showJsonPages(entity)
+	entityName := *selected.serviceDeployment.ServiceDeploymentArn
+	store := v.app.Store
+	var serviceRevision *types.ServiceRevision
+	v.loadSecondaryContent(Entity{entityName: entityName}, func(ctx context.Context) (err error) {
+		serviceRevision, err = store.GetServiceRevision(ctx, serviceRevisionArn)
+		return err
+	}, func(err error) error {
+		if err != nil {
+			v.app.Notice.Warnf("failed to get service revision, err: %v", err)
+			return err
+		}
+		entity := Entity{serviceRevision: serviceRevision, entityName: entityName}
+		v.showJsonPages(entity)
+		return nil
+	})
 }
 
 // Show new page from JSON content in table area and handle done event to go back
 func (v *view) showJsonPages(entity Entity) {
-	colorizedJsonString, rawJsonString, err := v.getJsonString(entity)
-	if err != nil {
-		return
+	if v.app.jsonTree {
+		_, rawJsonString, err := v.getJsonString(entity)
+		if err != nil {
+			return
+		}
+		v.showJsonTree(entity, rawJsonString)
+	} else {
+		content, raw, err := v.getDescribeString(entity)
+		if err != nil {
+			return
+		}
+		v.handleSecondaryPageSwitch(entity, content, raw)
 	}
-	v.handleSecondaryPageSwitch(entity, colorizedJsonString, rawJsonString)
 	v.handleHeaderPageSwitch(entity)
 }
 
-func (v *view) handleFullScreenContentInput(jsonBytes []byte) func(event *tcell.EventKey) *tcell.EventKey {
+func (v *view) handleFullScreenContentInput(page *textPage, jsonBytes []byte) func(event *tcell.EventKey) *tcell.EventKey {
 	return func(event *tcell.EventKey) *tcell.EventKey {
+		if v.handleTextSearchInput(page, event) == nil {
+			return nil
+		}
+
 		switch event.Rune() {
 		case 'f':
 			pageName := v.app.kind.getAppPageName(v.app.getPageHandle())
@@ -208,6 +238,20 @@ func (v *view) openInEditor(beforeJson []byte) {
 			return
 		}
 
+		// description pages are edited in their format
+		if !json.Valid(afterJson) {
+			switch v.app.describeFormat {
+			case describeYAML:
+				if afterJson, err = utils.YAMLToJSON(afterJson); err != nil {
+					v.app.Notice.Warnf("failed to convert YAML to JSON, err: %v", err)
+					return
+				}
+			case describeText:
+				v.app.Notice.Warn("Not support to update from describe format, switch to JSON or YAML")
+				return
+			}
+		}
+
 		// only task definition and edited json register task definition
 		var updatedTd ecs.RegisterTaskDefinitionInput
 		if err := json.Unmarshal(afterJson, &updatedTd); err != nil {
@@ -216,7 +260,7 @@ func (v *view) openInEditor(beforeJson []byte) {
 		}
 
 		register := func() {
-			family, revision, err := v.app.Store.RegisterTaskDefinition(&updatedTd)
+			family, revision, err := v.app.Store.RegisterTaskDefinition(context.Background(), &updatedTd)
 
 			if err != nil {
 				v.app.Notice.Warnf("failed to register new task definition, err: %v", err)
@@ -1,7 +1,10 @@
 package view
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -89,3 +92,42 @@ func TestGetJsonData(t *testing.T) {
 		})
 	}
 }
+
+func TestAutoScalingPageLoads(t *testing.T) {
+	v := demoServiceView(t, Option{})
+	app := v.app
+	runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		app.secondaryKind = AutoScalingKind
+		v.showSecondaryKindPage(false)
+	})
+	waitFor(t, app, func() bool {
+		name, _ := v.tablePages.GetFrontPage()
+		return app.load == nil && strings.Contains(name, "."+AutoScalingKind.String()+".") &&
+			strings.Contains(app.Notice.GetText(true), "Viewing autoscaling")
+	})
+}
+
+func TestSecondaryLoadFailedGoesBack(t *testing.T) {
+	v := demoServiceView(t, Option{})
+	app := v.app
+	runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		app.secondaryKind = ServiceRevisionKind
+		v.loadSecondaryContent(Entity{entityName: "revision"}, func(ctx context.Context) error {
+			return errors.New("AccessDeniedException")
+		}, func(err error) error {
+			if err != nil {
+				app.Notice.Warnf("failed to get service revision, err: %v", err)
+			}
+			return err
+		})
+	})
+	waitFor(t, app, func() bool {
+		name, _ := v.tablePages.GetFrontPage()
+		return app.load == nil && app.secondaryKind == EmptyKind && !strings.Contains(name, ".description") &&
+			strings.Contains(app.Notice.GetText(true), "AccessDeniedException")
+	})
+}
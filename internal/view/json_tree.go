@@ -0,0 +1,431 @@
+package view
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/rivo/tview"
+)
+
+// JSON value of the tree, object fields keep their order
+type jsonNode struct {
+	// object key or array index, empty for the root
+	key      string
+	raw      json.RawMessage
+	object   bool
+	array    bool
+	children []*jsonNode
+}
+
+// Parse JSON value with its nested values in one pass
+func parseJSONNode(key string, raw json.RawMessage) (*jsonNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	n, err := decodeJSONNode(decoder, raw, key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON value")
+	}
+	return n, nil
+}
+
+// Decode the next value of decoder, raw of each node is its slice of data
+func decodeJSONNode(decoder *json.Decoder, data []byte, key string) (*jsonNode, error) {
+	start := decoder.InputOffset()
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &jsonNode{key: key}
+	switch token {
+	case json.Delim('{'):
+		n.object = true
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			child, err := decodeJSONNode(decoder, data, token.(string))
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+		}
+	case json.Delim('['):
+		n.array = true
+		for i := 0; decoder.More(); i++ {
+			child, err := decodeJSONNode(decoder, data, strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+		}
+	}
+	if n.object || n.array {
+		// closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+	// separators before the value are read with it
+	n.raw = bytes.TrimLeft(data[start:decoder.InputOffset()], " \t\r\n,:")
+	return n, nil
+}
+
+// Indented JSON of value, strings are unquoted
+func (n *jsonNode) text() string {
+	var s string
+	if bytes.HasPrefix(n.raw, []byte(`"`)) && json.Unmarshal(n.raw, &s) == nil {
+		return s
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, n.raw, "", "  "); err != nil {
+		return string(n.raw)
+	}
+	return b.String()
+}
+
+// Step of a path query, a key, an index or all children
+type jsonPathStep struct {
+	key   string
+	index *int
+	all   bool
+}
+
+// Parse jq-like path like ".containerDefinitions[].environment" or JSONPath like "$.containerDefinitions[*].name"
+func parseJSONPath(expr string) ([]jsonPathStep, error) {
+	expr = strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	steps := []jsonPathStep{}
+	for i := 0; i < len(expr); {
+		switch expr[i] {
+		case '.':
+			i++
+			end := i
+			for end < len(expr) && expr[end] != '.' && expr[end] != '[' {
+				end++
+			}
+			switch name := expr[i:end]; name {
+			case "":
+				// "." alone or before "["
+				if end < len(expr) && expr[end] != '[' {
+					return nil, fmt.Errorf("missing key at %d", i)
+				}
+			case "*":
+				steps = append(steps, jsonPathStep{all: true})
+			default:
+				steps = append(steps, jsonPathStep{key: name})
+			}
+			i = end
+		case '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ] after %d", i)
+			}
+			inner := strings.TrimSpace(expr[i+1 : i+end])
+			switch {
+			case inner == "" || inner == "*":
+				steps = append(steps, jsonPathStep{all: true})
+			case len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q", inner)
+				}
+				steps = append(steps, jsonPathStep{index: &index})
+			}
+			i += end + 1
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d, paths start with \".\"", expr[i], i)
+		}
+	}
+	return steps, nil
+}
+
+// Values of root matching path query, keys fall back to case insensitive match.
+// Queries over all children return an array of the matched values.
+func queryJSON(root *jsonNode, expr string) (*jsonNode, error) {
+	steps, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []*jsonNode{root}
+	many := false
+	for _, step := range steps {
+		next := []*jsonNode{}
+		for _, n := range nodes {
+			switch {
+			case step.all:
+				next = append(next, n.children...)
+			case step.index != nil && n.array:
+				i := *step.index
+				if i < 0 {
+					i += len(n.children)
+				}
+				if i >= 0 && i < len(n.children) {
+					next = append(next, n.children[i])
+				}
+			case step.index == nil && n.object:
+				if child := n.child(step.key); child != nil {
+					next = append(next, child)
+				}
+			}
+		}
+		nodes = next
+		many = many || step.all
+	}
+
+	switch {
+	case len(nodes) == 0:
+		return nil, errors.New("no match")
+	case !many:
+		return nodes[0], nil
+	}
+	result := &jsonNode{array: true, children: nodes}
+	raws := [][]byte{}
+	for _, n := range nodes {
+		raws = append(raws, n.raw)
+	}
+	result.raw = json.RawMessage("[" + string(bytes.Join(raws, []byte(","))) + "]")
+	return result, nil
+}
+
+// Child of object by key, exact match first
+func (n *jsonNode) child(key string) *jsonNode {
+	for _, c := range n.children {
+		if c.key == key {
+			return c
+		}
+	}
+	for _, c := range n.children {
+		if strings.EqualFold(c.key, key) {
+			return c
+		}
+	}
+	return nil
+}
+
+// JSON tree page of JSON pages, / narrows the document by a path query
+type jsonTreePage struct {
+	*tview.Flex
+	tree  *tview.TreeView
+	input *tview.InputField
+	root  *jsonNode
+	title string
+	query string
+}
+
+func newJsonTreePage(jsonBytes []byte, title string) (*jsonTreePage, error) {
+	root, err := parseJSONNode("", jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	p := &jsonTreePage{
+		Flex:  tview.NewFlex().SetDirection(tview.FlexRow),
+		tree:  tview.NewTreeView(),
+		root:  root,
+		title: title,
+	}
+	p.tree.SetBorder(true).SetBorderPadding(0, 0, 1, 1)
+	p.tree.SetGraphicsColor(color.Color(theme.Gray))
+	p.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+		setJsonTreeNodeText(node)
+	})
+	p.AddItem(p.tree, 0, 1, true)
+	p.show("")
+	return p, nil
+}
+
+// Show values matching query, empty query shows the document
+func (p *jsonTreePage) show(query string) error {
+	n := p.root
+	if strings.TrimSpace(query) != "" {
+		var err error
+		if n, err = queryJSON(p.root, query); err != nil {
+			return err
+		}
+	}
+	p.query = strings.TrimSpace(query)
+	root := newJsonTreeNode(n, 0)
+	p.tree.SetRoot(root).SetCurrentNode(root)
+
+	title := p.title
+	if p.query != "" {
+		title += fmt.Sprintf("[black:blue]<%s>[-:-] ", tview.Escape(p.query))
+	}
+	p.tree.SetTitle(title)
+	return nil
+}
+
+// Show query input below the tree, the query runs when enter is pressed
+func (p *jsonTreePage) showQueryInput(app *App) {
+	if p.input != nil {
+		app.SetFocus(p.input)
+		return
+	}
+	p.input = tview.NewInputField().
+		SetLabel("[gray]🔍 path:[-] ").
+		SetLabelColor(color.Color(theme.Cyan)).
+		SetPlaceholder(".containerDefinitions[].environment").
+		SetText(p.query)
+	p.input.SetBackgroundColor(color.Color(theme.BgColor))
+	p.input.SetFieldBackgroundColor(color.Color(theme.BgColor))
+	p.input.SetFieldTextColor(color.Color(theme.FgColor))
+	p.input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			query := p.input.GetText()
+			if err := p.show(query); err != nil {
+				app.Notice.Warnf("invalid query %q, err: %v", query, err)
+				return
+			}
+		}
+		p.RemoveItem(p.input)
+		p.input = nil
+		app.SetFocus(p.tree)
+	})
+	p.AddItem(p.input, 1, 0, true)
+	app.SetFocus(p.input)
+}
+
+// Expand collapsed object or array of current node, false when there is none
+func (p *jsonTreePage) expandCurrent() bool {
+	node := p.tree.GetCurrentNode()
+	if node == nil || node.IsExpanded() || len(node.GetChildren()) == 0 {
+		return false
+	}
+	node.Expand()
+	setJsonTreeNodeText(node)
+	return true
+}
+
+// Collapse expanded object or array of current node, false when there is none
+func (p *jsonTreePage) collapseCurrent() bool {
+	node := p.tree.GetCurrentNode()
+	if node == nil || !node.IsExpanded() || len(node.GetChildren()) == 0 {
+		return false
+	}
+	node.Collapse()
+	setJsonTreeNodeText(node)
+	return true
+}
+
+// Tree node of value, the root and its children are expanded
+func newJsonTreeNode(n *jsonNode, depth int) *tview.TreeNode {
+	node := tview.NewTreeNode("").SetReference(n).SetExpanded(depth == 0)
+	for _, child := range n.children {
+		node.AddChild(newJsonTreeNode(child, depth+1))
+	}
+	setJsonTreeNodeText(node)
+	return node
+}
+
+func setJsonTreeNodeText(node *tview.TreeNode) {
+	n := node.GetReference().(*jsonNode)
+	key := ""
+	if n.key != "" {
+		key = fmt.Sprintf("[%s::b]%s[-:-:-]", theme.Blue, tview.Escape(n.key))
+	}
+	switch {
+	case n.object || n.array:
+		marker := "▸"
+		if node.IsExpanded() {
+			marker = "▾"
+		}
+		size := fmt.Sprintf("{%d}", len(n.children))
+		if n.array {
+			size = tview.Escape(fmt.Sprintf("[%d]", len(n.children)))
+		}
+		if key != "" {
+			marker += " " + key
+		}
+		node.SetText(marker + " " + size)
+	case key == "":
+		node.SetText(tview.Escape(string(n.raw)))
+	default:
+		node.SetText(key + ": " + tview.Escape(string(n.raw)))
+	}
+}
+
+// Show JSON page as a tree in table area
+func (v *view) showJsonTree(entity Entity, jsonBytes []byte) {
+	contentTitle := fmt.Sprintf(color.TableSecondaryTitleFmt, v.app.kind, entity.entityName, v.app.secondaryKind)
+	contentPageName := v.app.kind.getSecondaryPageName(entity.entityName + "." + v.app.secondaryKind.String())
+
+	p, err := newJsonTreePage(jsonBytes, contentTitle)
+	if err != nil {
+		v.app.Notice.Warnf("failed to parse JSON, err: %v", err)
+		return
+	}
+
+	// keep query of the page when it is rebuilt
+	if name, item := v.tablePages.GetFrontPage(); name == contentPageName {
+		if previous, ok := item.(*jsonTreePage); ok && previous.query != "" {
+			p.show(previous.query)
+		}
+	}
+
+	p.tree.SetDoneFunc(func(key tcell.Key) {
+		// esc clears the query before leaving the page
+		if key == tcell.KeyEsc && p.query != "" {
+			p.show("")
+			return
+		}
+		v.handleTableContentDone(key)
+	})
+	p.tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case '/':
+			p.showQueryInput(v.app)
+			return nil
+		case 'c':
+			if node := p.tree.GetCurrentNode(); node != nil {
+				v.app.copyToClipboard("JSON value", node.GetReference().(*jsonNode).text())
+			}
+			return nil
+		case 'T':
+			v.app.jsonTree = false
+			v.showJsonPages(entity)
+			return nil
+		case 'b':
+			v.openInBrowser()
+			return nil
+		case 'e':
+			v.openInEditor(jsonBytes)
+			return nil
+		}
+
+		switch {
+		// expand, or move to the first child like J
+		case event.Rune() == 'l', event.Key() == tcell.KeyRight:
+			if p.expandCurrent() {
+				return nil
+			}
+			return tcell.NewEventKey(tcell.KeyRune, 'J', tcell.ModNone)
+		// collapse, or move to the parent like K
+		case event.Rune() == 'h', event.Key() == tcell.KeyLeft:
+			if p.collapseCurrent() {
+				return nil
+			}
+			return tcell.NewEventKey(tcell.KeyRune, 'K', tcell.ModNone)
+		}
+
+		switch event.Key() {
+		case tcell.KeyCtrlR:
+			v.reloadResource(true)
+		case tcell.KeyCtrlZ:
+			v.handleTableContentDone(0)
+		}
+		return event
+	})
+
+	v.tablePages.AddPage(contentPageName, p, true, true)
+}
@@ -0,0 +1,192 @@
+package view
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+const taskDefinitionJson = `{
+  "Family": "web",
+  "ContainerDefinitions": [
+    {
+      "Name": "app",
+      "Environment": [
+        {"Name": "PORT", "Value": "8080"}
+      ]
+    },
+    {
+      "Name": "sidecar",
+      "Environment": []
+    }
+  ],
+  "Tags": {"team.name": "platform"}
+}`
+
+func TestParseJSONNode(t *testing.T) {
+	root, err := parseJSONNode("", json.RawMessage(`{"b": {"y": [1, {"z": null}], "x": "s"} , "a" :[ ] }`))
+	if err != nil {
+		t.Fatalf("parseJSONNode: %v", err)
+	}
+	// object fields keep their order and raw is the value without separators
+	got := []string{}
+	var walk func(n *jsonNode, path string)
+	walk = func(n *jsonNode, path string) {
+		got = append(got, path+"="+string(n.raw))
+		for _, c := range n.children {
+			walk(c, path+"/"+c.key)
+		}
+	}
+	walk(root, "")
+	want := []string{
+		`={"b": {"y": [1, {"z": null}], "x": "s"} , "a" :[ ] }`,
+		`/b={"y": [1, {"z": null}], "x": "s"}`,
+		`/b/y=[1, {"z": null}]`,
+		`/b/y/0=1`,
+		`/b/y/1={"z": null}`,
+		`/b/y/1/z=null`,
+		`/b/x="s"`,
+		`/a=[ ]`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Got %q, Want %q", got, want)
+	}
+	if !root.object || !root.children[0].children[0].array || root.children[1].children != nil {
+		t.Errorf("Got object %t and array %t, Want: object and array nodes", root.object, root.children[0].children[0].array)
+	}
+
+	for _, raw := range []string{"", "{", `{"a" 1}`, `{"a":1,}`, "[1,]", `{"a":1}x`, "[1] [2]", "tru"} {
+		if _, err := parseJSONNode("", json.RawMessage(raw)); err == nil {
+			t.Errorf("Want: error of invalid JSON %q", raw)
+		}
+	}
+}
+
+func TestQueryJSON(t *testing.T) {
+	root, err := parseJSONNode("", json.RawMessage(taskDefinitionJson))
+	if err != nil {
+		t.Fatalf("parseJSONNode: %v", err)
+	}
+	testCases := []struct {
+		query string
+		want  string
+	}{
+		{".", ""},
+		{".Family", `"web"`},
+		{".family", `"web"`},
+		{".containerDefinitions[0].name", `"app"`},
+		{".containerDefinitions[-1].name", `"sidecar"`},
+		{".containerDefinitions[].name", `["app","sidecar"]`},
+		{"$.ContainerDefinitions[*].Environment[*].Value", `["8080"]`},
+		{".containerDefinitions[].environment", `[[{"Name":"PORT","Value":"8080"}],[]]`},
+		{`.Tags["team.name"]`, `"platform"`},
+		{".Tags.*", `["platform"]`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			got, err := queryJSON(root, tc.query)
+			if err != nil {
+				t.Fatalf("queryJSON: %v", err)
+			}
+			want := tc.want
+			if want == "" {
+				want = taskDefinitionJson
+			}
+			if compactJSON(t, got.raw) != compactJSON(t, json.RawMessage(want)) {
+				t.Errorf("Got %s, Want %s", got.raw, want)
+			}
+		})
+	}
+
+	for _, query := range []string{"Family", ".Missing", ".Family[0]", ".ContainerDefinitions[x]", ".Tags[", ".."} {
+		if _, err := queryJSON(root, query); err == nil {
+			t.Errorf("Want: error of query %q", query)
+		}
+	}
+}
+
+func compactJSON(t *testing.T, raw json.RawMessage) string {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", raw, err)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestJsonTreePage(t *testing.T) {
+	p, err := newJsonTreePage([]byte(taskDefinitionJson), " title ")
+	if err != nil {
+		t.Fatalf("newJsonTreePage: %v", err)
+	}
+	root := p.tree.GetRoot()
+	if !root.IsExpanded() || len(root.GetChildren()) != 3 {
+		t.Fatalf("Want: root expanded with 3 fields")
+	}
+	containers := root.GetChildren()[1]
+	if containers.IsExpanded() || !strings.HasPrefix(containers.GetText(), "▸ ") {
+		t.Errorf("Got %q, Want: nested array collapsed", containers.GetText())
+	}
+
+	p.tree.SetCurrentNode(containers)
+	if !p.expandCurrent() || !strings.HasPrefix(containers.GetText(), "▾ ") {
+		t.Errorf("Got %q, Want: array expanded", containers.GetText())
+	}
+	if p.expandCurrent() {
+		t.Error("Want: expanded array not expanded again")
+	}
+	if !p.collapseCurrent() || containers.IsExpanded() {
+		t.Error("Want: array collapsed")
+	}
+
+	if err := p.show(".ContainerDefinitions[0]"); err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	current := p.tree.GetCurrentNode().GetReference().(*jsonNode)
+	if !strings.Contains(current.text(), `"Name": "app"`) || !strings.Contains(p.tree.GetTitle(), "<.ContainerDefinitions[0[]>") {
+		t.Errorf("Got %q, title %q, Want: first container", current.text(), p.tree.GetTitle())
+	}
+	if err := p.show(".Missing"); err == nil || p.query != ".ContainerDefinitions[0]" {
+		t.Errorf("Got query %q, Want: failed query keeps the shown one", p.query)
+	}
+
+	name := p.tree.GetCurrentNode().GetChildren()[0].GetReference().(*jsonNode)
+	if name.text() != "app" {
+		t.Errorf("Got %q, Want: unquoted string value", name.text())
+	}
+}
+
+func TestShowJsonTree(t *testing.T) {
+	app, _ := newApp(Option{})
+	app.kind = ClusterKind
+	app.secondaryKind = DescriptionKind
+	cluster := types.Cluster{ClusterName: aws.String(clusterName1)}
+	v := newView(app, []keyDescriptionPair{}, secondaryPageKeyMap{})
+	entity := Entity{cluster: &cluster, entityName: clusterName1}
+
+	app.jsonTree = true
+	v.showJsonPages(entity)
+	_, item := v.tablePages.GetFrontPage()
+	p, ok := item.(*jsonTreePage)
+	if !ok {
+		t.Fatalf("Got %T, Want: JSON tree page", item)
+	}
+	p.show(".ClusterName")
+
+	// reloading keeps the query
+	v.showJsonPages(entity)
+	_, item = v.tablePages.GetFrontPage()
+	if p := item.(*jsonTreePage); p.query != ".ClusterName" {
+		t.Errorf("Got query %q, Want: query kept", p.query)
+	}
+
+	app.jsonTree = false
+	v.showJsonPages(entity)
+	_, item = v.tablePages.GetFrontPage()
+	if _, ok := item.(*textPage); !ok {
+		t.Errorf("Got %T, Want: text page", item)
+	}
+}
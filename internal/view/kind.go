@@ -20,6 +20,9 @@ const (
 	EmptyKind
 	ProfileKind
 	RegionKind
+	MetricsKind
+	LogTailKind
+	LogsInsightsKind
 )
 
 func (k kind) String() string {
@@ -56,6 +59,12 @@ func (k kind) String() string {
 		return "profiles"
 	case RegionKind:
 		return "regions"
+	case MetricsKind:
+		return "metrics"
+	case LogTailKind:
+		return "live tail"
+	case LogsInsightsKind:
+		return "logs insights"
 	default:
 		return "unknownKind"
 	}
@@ -102,7 +111,7 @@ func (k kind) getAppPageName(name string) string {
 	case ProfileKind, RegionKind:
 		return k.String()
 	case ClusterKind:
-		return prefix + "." + k.String()
+		return profilesLabel() + "." + regionsLabel() + "." + k.String()
 	case ServiceKind, TaskKind, ContainerKind, TaskDefinitionKind, ServiceDeploymentKind, DescriptionKind, InstanceKind:
 		return prefix + "." + k.String() + "." + name
 	default:
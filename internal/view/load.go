@@ -0,0 +1,267 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
+)
+
+// Returned by page functions when the page is built later by a background load
+var errPageLoading = errors.New("page is loading")
+
+// Navigation state restored when a pending load is cancelled
+type navState struct {
+	kind        kind
+	backKind    kind
+	taskStatus  types.DesiredStatus
+	fromCluster bool
+}
+
+// Resource load running in background for the page being opened or reloaded
+type pendingLoad struct {
+	cancel context.CancelFunc
+	reload bool
+	// Page the load was started for, results for any other page are dropped
+	pageName string
+	failed   func()
+}
+
+func (app *App) navState() navState {
+	return navState{
+		kind:        app.kind,
+		backKind:    app.backKind,
+		taskStatus:  app.taskStatus,
+		fromCluster: app.fromCluster,
+	}
+}
+
+// Remember navigation state of the page on screen
+func (app *App) markShown() {
+	app.shown = app.navState()
+	// Content loaded for a page left behind is not needed anymore
+	if l := app.contentLoad; l != nil && l.pageName != app.kind.getAppPageName(app.getPageHandle()) {
+		app.stopContentLoad()
+	}
+}
+
+// Load resources for current kind page.
+// Before the app runs (startup, tests) fetch and build run synchronously.
+// Afterwards fetch runs in background so a slow region doesn't freeze the UI, build
+// runs on the main loop with the result and errPageLoading is returned immediately.
+func (app *App) loadPage(reload bool, fetch func(ctx context.Context) error, build func(err error) error) error {
+	app.stopLoad()
+	ctx, cancel := context.WithCancel(context.Background())
+	if app.bypassCache {
+		ctx = api.WithoutCache(ctx)
+	}
+	failed := app.loadFailed
+	app.loadFailed = nil
+
+	if !app.running {
+		defer cancel()
+		err := build(fetch(ctx))
+		if err != nil && !errors.Is(err, ErrHandledNavigation) && !isPartial(err) && failed != nil {
+			failed()
+		}
+		app.refreshStatus.Done(err == nil || isPartial(err))
+		return err
+	}
+
+	l := &pendingLoad{
+		cancel:   cancel,
+		reload:   reload,
+		pageName: app.kind.getAppPageName(app.getPageHandle()),
+		failed:   failed,
+	}
+	app.load = l
+	app.refreshStatus.Start()
+	// Reloads keep the page usable, the footer spinner is enough
+	if !reload {
+		app.Notice.Loading("loading… (esc to cancel)")
+	}
+	ctx = app.withRetryNotice(ctx, l)
+
+	go func() {
+		err := fetch(ctx)
+		app.dispatch(func() {
+			// Cancelled or replaced by another load
+			if app.load != l {
+				return
+			}
+			app.load = nil
+			cancel()
+			if !reload {
+				app.Notice.Clear()
+			}
+			if app.kind.getAppPageName(app.getPageHandle()) != l.pageName || (reload && !app.canAutoRefresh()) {
+				slog.Debug("Drop load for hidden page", "pageName", l.pageName)
+				app.refreshStatus.Done(false)
+				return
+			}
+			finish := func() {
+				err := app.pageShown(build(err), reload)
+				app.refreshStatus.Done(err == nil)
+				// Credentials may have been refreshed with a new expiry
+				if err == nil && reload {
+					app.refreshContext()
+				}
+				if err != nil && l.failed != nil {
+					l.failed()
+				}
+			}
+			if profile, expired := app.Store.ExpiredCredentials(err); expired && !isPartial(err) {
+				app.refreshStatus.Done(false)
+				app.offerSsoLogin(profile, func() {
+					app.loadFailed = failed
+					app.loadPage(reload, fetch, build)
+				}, finish)
+				return
+			}
+			finish()
+		})
+	}()
+	return errPageLoading
+}
+
+// Load content of a secondary page in background, esc cancels it like a page load.
+// build runs on the main loop with the result, the page on screen stays meanwhile.
+func (app *App) loadSecondaryPage(fetch func(ctx context.Context) error, build func(err error) error) {
+	app.stopLoad()
+	ctx, cancel := context.WithCancel(context.Background())
+	failed := app.loadFailed
+	app.loadFailed = nil
+	done := func(err error) {
+		if err := build(err); err != nil && failed != nil {
+			failed()
+		}
+	}
+
+	if !app.running {
+		defer cancel()
+		done(fetch(ctx))
+		return
+	}
+
+	l := &pendingLoad{
+		cancel:   cancel,
+		pageName: app.kind.getAppPageName(app.getPageHandle()),
+		failed:   failed,
+	}
+	app.load = l
+	app.Notice.Loading("loading… (esc to cancel)")
+	ctx = app.withRetryNotice(ctx, l)
+
+	go func() {
+		err := fetch(ctx)
+		app.dispatch(func() {
+			// Cancelled or replaced by another load
+			if app.load != l {
+				return
+			}
+			app.load = nil
+			cancel()
+			app.Notice.Clear()
+			done(err)
+		})
+	}()
+}
+
+// Load extra content of the page on screen in background, e.g. utilization columns.
+// The page stays usable meanwhile, ctx is cancelled once another page is shown or the
+// app closes and done runs on the main loop only while the page is still on screen.
+func (app *App) loadPageContent(fetch func(ctx context.Context) error, done func(err error)) {
+	app.stopContentLoad()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if !app.running {
+		defer cancel()
+		done(fetch(ctx))
+		return
+	}
+
+	l := &pendingLoad{
+		cancel:   cancel,
+		pageName: app.kind.getAppPageName(app.getPageHandle()),
+	}
+	app.contentLoad = l
+
+	go func() {
+		err := fetch(ctx)
+		app.dispatch(func() {
+			// Cancelled or replaced by another content load
+			if app.contentLoad != l {
+				return
+			}
+			app.contentLoad = nil
+			cancel()
+			if app.kind.getAppPageName(app.getPageHandle()) != l.pageName {
+				slog.Debug("Drop content load for hidden page", "pageName", l.pageName)
+				return
+			}
+			done(err)
+		})
+	}()
+}
+
+// Stop pending content load of the page on screen
+func (app *App) stopContentLoad() {
+	l := app.contentLoad
+	if l == nil {
+		return
+	}
+	app.contentLoad = nil
+	l.cancel()
+	slog.Debug("Stop content load", "pageName", l.pageName)
+}
+
+// Show throttled retries of load l in the notice
+func (app *App) withRetryNotice(ctx context.Context, l *pendingLoad) context.Context {
+	return api.WithRetryNotify(ctx, func(attempt, maxAttempts int) {
+		app.dispatch(func() {
+			if app.load == l {
+				app.Notice.Loading(fmt.Sprintf("throttled, retrying (%d/%d)… (esc to cancel)", attempt, maxAttempts))
+			}
+		})
+	})
+}
+
+// Partial results are shown with a warning instead of failing the page
+func isPartial(err error) bool {
+	var partial *api.PartialError
+	return errors.As(err, &partial)
+}
+
+// Stop pending load without touching navigation state
+func (app *App) stopLoad() *pendingLoad {
+	l := app.load
+	if l == nil {
+		return nil
+	}
+	app.load = nil
+	l.cancel()
+	app.refreshStatus.Done(false)
+	slog.Debug("Stop load", "pageName", l.pageName)
+	return l
+}
+
+// Cancel pending load, navigation state goes back to the page on screen
+func (app *App) cancelLoad() {
+	l := app.stopLoad()
+	if l == nil {
+		return
+	}
+	if !l.reload {
+		app.kind = app.shown.kind
+		app.backKind = app.shown.backKind
+		app.taskStatus = app.shown.taskStatus
+		app.fromCluster = app.shown.fromCluster
+	}
+	if l.failed != nil {
+		l.failed()
+	}
+	app.Notice.Clear()
+}
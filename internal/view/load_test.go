@@ -0,0 +1,397 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	ssooidcTypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/keidarcy/e1s/internal/api"
+)
+
+// Run app on a simulation screen, app state must be read with app.QueueUpdate
+func runTestApp(t *testing.T, app *App) tcell.SimulationScreen {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	app.SetScreen(screen)
+	app.SetInputCapture(app.globalInputHandle)
+	app.SetRoot(app.mainScreen, true)
+	app.running = true
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		app.Run()
+	}()
+	// globals are restored only once the main loop is done with them
+	t.Cleanup(func() {
+		app.Stop()
+		<-stopped
+	})
+	return screen
+}
+
+func waitFor(t *testing.T, app *App, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		ok := false
+		app.QueueUpdate(func() { ok = cond() })
+		if ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("condition not met before deadline")
+}
+
+func TestLoadPageInBackground(t *testing.T) {
+	app := demoApp(t, Option{})
+	runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		if err := app.showPrimaryKindPage(ClusterKind, false); err != nil {
+			t.Errorf("show clusters page: %v", err)
+		}
+	})
+	waitFor(t, app, func() bool {
+		return app.load == nil && app.Pages.HasPage(ClusterKind.getAppPageName(""))
+	})
+}
+
+func TestEscCancelsLoad(t *testing.T) {
+	app := demoApp(t, Option{})
+	screen := runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		app.showPrimaryKindPage(ClusterKind, false)
+	})
+	waitFor(t, app, func() bool { return app.load == nil })
+
+	cancelled := make(chan struct{})
+	failed := false
+	app.QueueUpdate(func() {
+		app.kind = ServiceKind
+		app.loadFailed = func() { failed = true }
+		app.loadPage(false, func(ctx context.Context) error {
+			<-ctx.Done()
+			close(cancelled)
+			return ctx.Err()
+		}, func(err error) error {
+			t.Error("page built after load was cancelled")
+			return err
+		})
+	})
+
+	screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("load was not cancelled")
+	}
+	waitFor(t, app, func() bool {
+		return app.load == nil && app.kind == ClusterKind && failed
+	})
+}
+
+func TestContentLoadStopsWhenPageLeft(t *testing.T) {
+	app := demoApp(t, Option{})
+	runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		app.showPrimaryKindPage(ClusterKind, false)
+	})
+	waitFor(t, app, func() bool { return app.load == nil })
+
+	cancelled := make(chan struct{})
+	app.QueueUpdate(func() {
+		app.loadPageContent(func(ctx context.Context) error {
+			<-ctx.Done()
+			close(cancelled)
+			return ctx.Err()
+		}, func(err error) {
+			t.Error("content set on a page left behind")
+		})
+	})
+	app.QueueUpdate(func() {
+		app.showPrimaryKindPage(ServiceKind, false)
+	})
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("content load was not cancelled")
+	}
+	waitFor(t, app, func() bool { return app.load == nil && app.kind == ServiceKind && app.contentLoad == nil })
+}
+
+func TestEscDuringReloadGoesBack(t *testing.T) {
+	app := demoApp(t, Option{})
+	screen := runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		app.showPrimaryKindPage(ClusterKind, false)
+	})
+	waitFor(t, app, func() bool { return app.load == nil })
+	app.QueueUpdate(func() {
+		app.showPrimaryKindPage(ServiceKind, false)
+	})
+	waitFor(t, app, func() bool { return app.load == nil && app.kind == ServiceKind })
+
+	cancelled := make(chan struct{})
+	app.QueueUpdate(func() {
+		app.loadPage(true, func(ctx context.Context) error {
+			<-ctx.Done()
+			close(cancelled)
+			return ctx.Err()
+		}, func(err error) error {
+			t.Error("page built after reload was stopped")
+			return err
+		})
+	})
+
+	screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload was not stopped")
+	}
+	waitFor(t, app, func() bool { return app.load == nil && app.kind == ClusterKind })
+}
+
+func TestEscCancelsSecondaryLoad(t *testing.T) {
+	app := demoApp(t, Option{})
+	screen := runTestApp(t, app)
+
+	cancelled := make(chan struct{})
+	failed := false
+	app.QueueUpdate(func() {
+		app.kind = ClusterKind
+		app.secondaryKind = MetricsKind
+		app.loadFailed = func() { app.secondaryKind = EmptyKind; failed = true }
+		app.loadSecondaryPage(func(ctx context.Context) error {
+			<-ctx.Done()
+			close(cancelled)
+			return ctx.Err()
+		}, func(err error) error {
+			t.Error("page built after load was cancelled")
+			return err
+		})
+	})
+
+	screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("load was not cancelled")
+	}
+	waitFor(t, app, func() bool {
+		return app.load == nil && failed && app.secondaryKind == EmptyKind
+	})
+}
+
+func TestPartialResultsShown(t *testing.T) {
+	app := demoApp(t, Option{})
+	clusters, err := app.Store.ListClusters(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := false
+	app.loadFailed = func() { failed = true }
+	err = app.loadPage(false, func(ctx context.Context) error {
+		return &api.PartialError{Err: errors.New("ThrottlingException")}
+	}, func(err error) error {
+		return buildResourcePage(clusters, app, err, func() resourceViewBuilder {
+			return newClusterView(clusters, app)
+		})
+	})
+	if !isPartial(err) {
+		t.Fatalf("Got error: %v, Want: partial error", err)
+	}
+	if err := app.pageShown(err, false); err != nil {
+		t.Errorf("Got error: %v, Want: nil", err)
+	}
+	if failed {
+		t.Error("partial results treated as a failed load")
+	}
+	if !app.Pages.HasPage(ClusterKind.getAppPageName(app.getPageHandle())) {
+		t.Error("cluster page not added")
+	}
+}
+
+func TestExpiredCredentialsOfferLogin(t *testing.T) {
+	tokenPath, err := ssocreds.StandardCachedTokenFilepath("https://example.awsapps.com/start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name  string
+		err   error
+		login bool
+	}{
+		{
+			name:  "expired sso token",
+			err:   &ssocreds.InvalidTokenError{},
+			login: true,
+		},
+		{
+			name:  "sso-session token refresh failed",
+			err:   fmt.Errorf("refresh cached SSO token failed, %w", fmt.Errorf("unable to refresh SSO token, %w", &ssooidcTypes.InvalidGrantException{})),
+			login: true,
+		},
+		{
+			name:  "sso-session token missing",
+			err:   fmt.Errorf("failed to read cached SSO token file, %w", &fs.PathError{Op: "open", Path: tokenPath, Err: fs.ErrNotExist}),
+			login: true,
+		},
+		{
+			name: "other error mentioning SSO token",
+			err:  errors.New("invalid SSO token scope"),
+		},
+		{
+			name: "other missing file",
+			err:  &fs.PathError{Op: "open", Path: filepath.Join(t.TempDir(), "task.json"), Err: fs.ErrNotExist},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := demoApp(t, Option{})
+			screen := runTestApp(t, app)
+
+			failed := false
+			app.QueueUpdate(func() {
+				app.kind = ClusterKind
+				app.loadFailed = func() { failed = true }
+				app.loadPage(false, func(ctx context.Context) error {
+					return tc.err
+				}, func(err error) error {
+					return err
+				})
+			})
+			if !tc.login {
+				waitFor(t, app, func() bool { return failed })
+				app.QueueUpdate(func() {
+					if app.Pages.HasPage(ssoLoginPage) {
+						t.Error("Got SSO login offered, Want: load failed")
+					}
+				})
+				return
+			}
+
+			waitFor(t, app, func() bool { return app.Pages.HasPage(ssoLoginPage) })
+			app.QueueUpdate(func() {
+				if failed {
+					t.Error("load failed before login was declined")
+				}
+			})
+			screen.InjectKey(tcell.KeyESC, 0, tcell.ModNone)
+			waitFor(t, app, func() bool { return failed && !app.Pages.HasPage(ssoLoginPage) })
+		})
+	}
+}
+
+func TestReloadInBackground(t *testing.T) {
+	app := demoApp(t, Option{})
+	runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		app.showPrimaryKindPage(ClusterKind, false)
+	})
+	waitFor(t, app, func() bool { return app.load == nil })
+
+	release := make(chan struct{})
+	built := false
+	app.QueueUpdate(func() {
+		app.loadPage(true, func(ctx context.Context) error {
+			<-release
+			return nil
+		}, func(err error) error {
+			built = true
+			return err
+		})
+	})
+
+	// Main loop keeps serving while the reload is blocked
+	waitFor(t, app, func() bool {
+		return strings.Contains(app.refreshStatus.GetText(true), "refreshing")
+	})
+	close(release)
+	waitFor(t, app, func() bool {
+		return built && strings.Contains(app.refreshStatus.GetText(true), "refreshed")
+	})
+}
+
+func TestAutoRefreshSkipsCache(t *testing.T) {
+	clusterArn := "arn:aws:ecs:us-east-1:111111:cluster/cached"
+	newService := func(name string) types.Service {
+		return types.Service{
+			ServiceName: aws.String(name),
+			ServiceArn:  aws.String("arn:aws:ecs:us-east-1:111111:service/cached/" + name),
+			ClusterArn:  aws.String(clusterArn),
+		}
+	}
+	fixtures := &api.Fixtures{
+		Region:   "us-east-1",
+		Clusters: []types.Cluster{{ClusterName: aws.String("cached"), ClusterArn: aws.String(clusterArn)}},
+		Services: []types.Service{newService("web")},
+	}
+	app := demoApp(t, Option{})
+	app.Store = api.NewFixtureStore(fixtures)
+	app.cluster = &fixtures.Clusters[0]
+	if err := app.showPrimaryKindPage(ServiceKind, false); err != nil {
+		t.Fatalf("show services page: %v", err)
+	}
+
+	fixtures.Services = append(fixtures.Services, newService("api"))
+	app.autoRefresh()
+	// the refresh fetched and cached the new service
+	services, err := app.Store.ListServices(context.Background(), aws.String("cached"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 2 || app.bypassCache {
+		t.Errorf("Got %d services, Want: 2 services fetched by auto refresh", len(services))
+	}
+}
+
+func TestNoticeFromGoroutines(t *testing.T) {
+	app := demoApp(t, Option{})
+	runTestApp(t, app)
+
+	done := make(chan struct{})
+	for i := range 10 {
+		go func() {
+			app.Notice.Infof("message %d", i)
+			app.dispatch(func() {
+				app.refreshStatus.Done(true)
+			})
+			done <- struct{}{}
+		}()
+	}
+	for range 10 {
+		<-done
+	}
+	waitFor(t, app, func() bool {
+		return strings.Contains(app.Notice.GetText(true), "message") && app.refreshStatus.GetText(true) != ""
+	})
+}
+
+func TestContextBarShowsIdentity(t *testing.T) {
+	app := demoApp(t, Option{})
+	runTestApp(t, app)
+
+	app.QueueUpdate(app.refreshContext)
+	waitFor(t, app, func() bool {
+		text := app.contextBar.GetText(true)
+		return strings.Contains(text, "123456789012 (demo)") && strings.Contains(text, "demo/e1s") && strings.Contains(text, globalRegion)
+	})
+}
@@ -0,0 +1,223 @@
+package view
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/rivo/tview"
+)
+
+// Rendering of structured log messages, set in config file as log-format
+type LogFormat struct {
+	// JSON fields of log level, the first present one is shown
+	LevelFields []string `mapstructure:"level-fields"`
+	// JSON fields of log message, the first present one is shown
+	MessageFields []string `mapstructure:"message-fields"`
+	// Regex highlight rules applied to every log message
+	Highlights []LogHighlight `mapstructure:"highlights"`
+}
+
+// Text matching Pattern is shown in Color, a color name or hex code
+type LogHighlight struct {
+	Pattern string `mapstructure:"pattern"`
+	Color   string `mapstructure:"color"`
+}
+
+var (
+	defaultLogLevelFields   = []string{"level", "severity", "lvl", "log.level"}
+	defaultLogMessageFields = []string{"msg", "message"}
+)
+
+type logHighlight struct {
+	pattern *regexp.Regexp
+	color   string
+}
+
+// Renders JSON log messages as "level  msg  key=value…" and highlights text of all messages
+type logFormatter struct {
+	levelFields   []string
+	messageFields []string
+	highlights    []logHighlight
+}
+
+// Formatter of option, invalid highlight patterns are skipped
+func newLogFormatter(option LogFormat) *logFormatter {
+	f := &logFormatter{
+		levelFields:   option.LevelFields,
+		messageFields: option.MessageFields,
+	}
+	if len(f.levelFields) == 0 {
+		f.levelFields = defaultLogLevelFields
+	}
+	if len(f.messageFields) == 0 {
+		f.messageFields = defaultLogMessageFields
+	}
+	for _, h := range option.Highlights {
+		pattern, err := regexp.Compile(h.Pattern)
+		if err != nil {
+			slog.Warn("invalid log highlight pattern", "pattern", h.Pattern, "error", err)
+			continue
+		}
+		f.highlights = append(f.highlights, logHighlight{pattern: pattern, color: h.Color})
+	}
+	return f
+}
+
+// Field of a JSON log message in original order
+type logField struct {
+	key   string
+	value json.RawMessage
+}
+
+// Parse message as a JSON object keeping field order, false if it is not one
+func parseJSONLog(message string) ([]logField, bool) {
+	message = strings.TrimSpace(message)
+	if !strings.HasPrefix(message, "{") || !json.Valid([]byte(message)) {
+		return nil, false
+	}
+	decoder := json.NewDecoder(strings.NewReader(message))
+	if _, err := decoder.Token(); err != nil {
+		return nil, false
+	}
+	fields := []logField{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		fields = append(fields, logField{key: token.(string), value: value})
+	}
+	return fields, true
+}
+
+// Colored one line text of message, JSON messages are rendered as level, message and the other fields
+func (f *logFormatter) format(message string) string {
+	fields, ok := parseJSONLog(message)
+	if !ok {
+		return f.highlight(message)
+	}
+
+	level, levelIndex := f.firstString(fields, f.levelFields)
+	msg, msgIndex := f.firstString(fields, f.messageFields)
+	parts := []string{}
+	if levelIndex >= 0 {
+		parts = append(parts, fmt.Sprintf(logLevelFmt(level), fmt.Sprintf("%-5s", strings.ToUpper(level))))
+	}
+	if msgIndex >= 0 {
+		parts = append(parts, f.highlight(msg))
+	}
+	pairs := []string{}
+	for i, field := range fields {
+		if i == levelIndex || i == msgIndex {
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf(color.GrayFmt, tview.Escape(field.key)+"=")+f.highlight(logValue(field.value)))
+	}
+	if len(pairs) > 0 {
+		parts = append(parts, strings.Join(pairs, " "))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// Indented JSON of message, false if it is not a JSON object
+func (f *logFormatter) pretty(message string) (string, bool) {
+	if _, ok := parseJSONLog(message); !ok {
+		return "", false
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, []byte(strings.TrimSpace(message)), "", "  "); err != nil {
+		return "", false
+	}
+	return f.highlight(b.String()), true
+}
+
+// First string value of names in fields and its index, -1 if none is present
+func (f *logFormatter) firstString(fields []logField, names []string) (string, int) {
+	for _, name := range names {
+		i := slices.IndexFunc(fields, func(field logField) bool { return field.key == name })
+		if i < 0 {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(fields[i].value, &s); err == nil {
+			return s, i
+		}
+	}
+	return "", -1
+}
+
+// Escaped text with highlight rules applied, earlier rules win on overlapping matches
+func (f *logFormatter) highlight(text string) string {
+	type match struct {
+		start, end int
+		color      string
+	}
+	matches := []match{}
+	for _, h := range f.highlights {
+		for _, loc := range h.pattern.FindAllStringIndex(text, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			overlap := slices.ContainsFunc(matches, func(m match) bool { return loc[0] < m.end && m.start < loc[1] })
+			if !overlap {
+				matches = append(matches, match{start: loc[0], end: loc[1], color: h.color})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return tview.Escape(text)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(tview.Escape(text[last:m.start]))
+		b.WriteString(fmt.Sprintf("[%s]%s[-]", m.color, tview.Escape(text[m.start:m.end])))
+		last = m.end
+	}
+	b.WriteString(tview.Escape(text[last:]))
+	return b.String()
+}
+
+// String values are unquoted unless they contain spaces, other values are compact JSON
+func logValue(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			return strconv.Quote(s)
+		}
+		return s
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, value); err != nil {
+		return string(value)
+	}
+	return b.String()
+}
+
+// Color format of a log level
+func logLevelFmt(level string) string {
+	switch strings.ToLower(level) {
+	case "error", "err", "fatal", "critical", "crit", "panic", "emergency", "alert":
+		return color.RedFmt
+	case "warn", "warning":
+		return color.YellowFmt
+	case "info", "notice":
+		return color.GreenFmt
+	default:
+		return color.GrayFmt
+	}
+}
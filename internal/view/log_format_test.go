@@ -0,0 +1,92 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/keidarcy/e1s/internal/color"
+)
+
+func TestLogFormat(t *testing.T) {
+	theme = color.InitStyles("")
+	f := newLogFormatter(LogFormat{})
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "JSON message",
+			message: `{"level":"warn","msg":"slow upstream","upstream":"api","duration_ms":1840,"tags":["a","b"]}`,
+			want:    fmt.Sprintf(color.YellowFmt, "WARN ") + "  slow upstream  " + fmt.Sprintf(color.GrayFmt, "upstream=") + "api " + fmt.Sprintf(color.GrayFmt, "duration_ms=") + "1840 " + fmt.Sprintf(color.GrayFmt, "tags=") + `["a","b"[]`,
+		},
+		{
+			name:    "quoted values with spaces",
+			message: `{"msg":"failed","error":"context deadline exceeded"}`,
+			want:    "failed  " + fmt.Sprintf(color.GrayFmt, "error=") + `"context deadline exceeded"`,
+		},
+		{
+			name:    "plain message is escaped",
+			message: "[info][main] initializing epoch 0",
+			want:    "[info[][main[] initializing epoch 0",
+		},
+		{
+			name:    "invalid JSON",
+			message: `{"level":"info"`,
+			want:    `{"level":"info"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.format(tt.message); got != tt.want {
+				t.Errorf("Got %q, Want: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogFormatFields(t *testing.T) {
+	theme = color.InitStyles("")
+	f := newLogFormatter(LogFormat{LevelFields: []string{"severity"}, MessageFields: []string{"event"}})
+	got := f.format(`{"level":"debug","severity":"ERROR","event":"boom"}`)
+	want := fmt.Sprintf(color.RedFmt, "ERROR") + "  boom  " + fmt.Sprintf(color.GrayFmt, "level=") + "debug"
+	if got != want {
+		t.Errorf("Got %q, Want: %q", got, want)
+	}
+}
+
+func TestLogHighlights(t *testing.T) {
+	f := newLogFormatter(LogFormat{Highlights: []LogHighlight{
+		{Pattern: `timeout|deadline exceeded`, Color: "red"},
+		{Pattern: `upstream \w+`, Color: "yellow"},
+		{Pattern: `(`, Color: "blue"},
+	}})
+	if len(f.highlights) != 2 {
+		t.Fatalf("Got %d rules, Want: invalid pattern skipped", len(f.highlights))
+	}
+	got := f.format("[warning] upstream api response timeout")
+	want := "[warning[] [yellow]upstream api[-] response [red]timeout[-]"
+	if got != want {
+		t.Errorf("Got %q, Want: %q", got, want)
+	}
+	// overlapping match of a later rule is skipped
+	if got := f.format("upstream timeout"); got != "upstream [red]timeout[-]" {
+		t.Errorf("Got %q, Want: first rule wins", got)
+	}
+}
+
+func TestLogPretty(t *testing.T) {
+	f := newLogFormatter(LogFormat{})
+	got, ok := f.pretty(`{"level":"info","port":8080}`)
+	if !ok || got != "{\n  \"level\": \"info\",\n  \"port\": 8080\n}" {
+		t.Errorf("Got %q, Want: indented JSON", got)
+	}
+	if _, ok := f.pretty("plain text"); ok {
+		t.Error("Want: plain text not expanded")
+	}
+	if !strings.Contains(f.format(`{"msg":"a [b]"}`), "a [b[]") {
+		t.Error("Want: JSON values escaped")
+	}
+}
@@ -0,0 +1,524 @@
+package view
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchlogsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/keidarcy/e1s/internal/ui"
+	"github.com/keidarcy/e1s/internal/utils"
+	"github.com/rivo/tview"
+)
+
+const (
+	logStreamFmt = "Log stream name: %s\n"
+	// timestamp, colored container name and message
+	mergedLogFmt = "[aqua::]%s[-:-:-] %s %s\n"
+	// option of log stream dropdown to read the latest log stream of each task
+	latestLogStreams = "latest of each task"
+	// prefix of selected JSON line
+	logLineMarker = "▶ "
+	// indent of expanded JSON lines
+	logExpandIndent = "    "
+)
+
+// Time ranges of log pages, the last one reads events until a timestamp
+var logRanges = []struct {
+	name  string
+	since time.Duration
+}{
+	{"latest", 0},
+	{"since 15m", 15 * time.Minute},
+	{"since 1h", time.Hour},
+	{"since 1d", 24 * time.Hour},
+	{"until timestamp", 0},
+}
+
+// Index of logRanges reading events until a timestamp
+var logRangeUntil = len(logRanges) - 1
+
+// Log page of a resource, older events of each log stream are loaded on demand
+type logPager struct {
+	// entity name of the resource
+	key string
+	// selected log stream of a service, nil for the latest log stream of each task
+	stream *api.LogStream
+	// log page of a service, other log streams are chosen in the time range form
+	service    bool
+	rangeIndex int
+	until      time.Time
+	// range of loaded pages
+	r     api.LogRange
+	limit int32
+	pages []*api.LogStreamPage
+	// containers left out of merged task logs
+	hidden map[string]bool
+	format *logFormatter
+	// keys of JSON lines in rendered order, the selected one and the ones shown as indented JSON
+	jsonLines []string
+	selected  string
+	expanded  map[string]bool
+	// row of selected line in rendered text
+	selectedRow int
+}
+
+// Show log page of entity, loads the latest events with range and stream of the last page of the same entity
+func (v *view) showLogs(entity Entity) {
+	p := v.app.logs
+	if p == nil || p.key != entity.entityName {
+		p = &logPager{key: entity.entityName, limit: api.ContainerLogLimit, format: v.app.logFormat}
+		if entity.service != nil {
+			p.service = true
+			p.limit = api.ServiceLogLimit
+		}
+		v.app.logs = p
+	}
+	v.loadLogs(p, entity)
+}
+
+// Load the latest page of entity log streams in range in background and show the log page
+func (v *view) loadLogs(p *logPager, entity Entity) {
+	tdArn, taskId, containerName := v.logScope(entity)
+	store, stream, limit := v.app.Store, p.stream, p.limit
+	r := api.LogRange{}
+	if since := logRanges[p.rangeIndex].since; since > 0 {
+		r.Since = time.Now().Add(-since)
+	}
+	if p.rangeIndex == logRangeUntil {
+		r.Until = p.until
+	}
+	var serviceName, clusterName *string
+	if entity.service != nil {
+		serviceName, clusterName = entity.service.ServiceName, v.app.cluster.ClusterName
+	}
+
+	var pages []*api.LogStreamPage
+	var failed []string
+	v.loadSecondaryContent(entity, func(ctx context.Context) error {
+		if tdArn == nil {
+			return fmt.Errorf("no task definition of %s", entity.entityName)
+		}
+		var streams []api.LogStream
+		var err error
+		switch {
+		case stream != nil:
+			streams = []api.LogStream{*stream}
+		case serviceName != nil:
+			streams, err = serviceLogStreams(ctx, store, tdArn, clusterName, serviceName)
+		default:
+			streams, err = store.GetTaskLogStreams(ctx, tdArn, taskId, containerName)
+		}
+		if err != nil {
+			return err
+		}
+		for _, s := range streams {
+			page, err := store.GetLogEventsPage(ctx, s, r, nil, limit)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				failed = append(failed, s.Name)
+				continue
+			}
+			pages = append(pages, page)
+		}
+		return nil
+	}, func(err error) error {
+		if err != nil {
+			v.app.Notice.Warnf("failed to load logs, err: %v", err)
+			return err
+		}
+		p.r = r
+		p.pages = pages
+		v.showLogPage(entity)
+		if len(failed) > 0 {
+			v.app.Notice.Warnf("failed to load log streams %s", strings.Join(failed, ", "))
+		}
+		return nil
+	})
+}
+
+// Newest log stream of each running task of service, latest first in each log group
+func serviceLogStreams(ctx context.Context, store *api.Store, tdArn, clusterName, serviceName *string) ([]api.LogStream, error) {
+	streams, err := store.GetServiceLogStreams(ctx, tdArn)
+	if err != nil {
+		return nil, err
+	}
+	tasks, _, err := store.ListTasks(ctx, clusterName, serviceName, types.DesiredStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+	taskIds := []string{}
+	for _, t := range tasks {
+		taskIds = append(taskIds, utils.ArnToName(t.TaskArn))
+	}
+	return api.TaskLogStreams(streams, taskIds), nil
+}
+
+// Load the previous page of each log stream in background and show the log page again
+func (v *view) loadOlderLogs(entity Entity) {
+	p := v.app.logs
+	if p == nil || p.key != entity.entityName {
+		return
+	}
+	store, r, limit := v.app.Store, p.r, p.limit
+	// pages are changed on the main loop only, fetch reads their streams and tokens
+	pages := slices.Clone(p.pages)
+	streams := make([]api.LogStream, len(pages))
+	tokens := make([]*string, len(pages))
+	for i, page := range pages {
+		streams[i], tokens[i] = page.LogStream, page.BackwardToken
+	}
+	older := make([]*api.LogStreamPage, len(pages))
+	var failed []string
+	v.loadSecondaryContent(entity, func(ctx context.Context) error {
+		for i, token := range tokens {
+			if token == nil {
+				continue
+			}
+			o, err := store.GetLogEventsPage(ctx, streams[i], r, token, limit)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				failed = append(failed, streams[i].Name)
+				continue
+			}
+			older[i] = o
+		}
+		return nil
+	}, func(err error) error {
+		if err != nil {
+			v.app.Notice.Warnf("failed to load older logs, err: %v", err)
+			return err
+		}
+		loaded := 0
+		for i, o := range older {
+			// pages replaced by a reload meanwhile are left as they are
+			if o == nil || !slices.Contains(p.pages, pages[i]) {
+				continue
+			}
+			pages[i].Events = append(o.Events, pages[i].Events...)
+			pages[i].BackwardToken = o.BackwardToken
+			loaded += len(o.Events)
+		}
+		v.showLogPage(entity)
+		switch {
+		case len(failed) > 0:
+			v.app.Notice.Warnf("failed to load older logs of %s", strings.Join(failed, ", "))
+		case loaded == 0:
+			v.app.Notice.Info("No older log events")
+		default:
+			v.app.Notice.Infof("Loaded %d older log events", loaded)
+		}
+		return nil
+	})
+}
+
+// Show loaded log page without reloading it
+func (v *view) showLogPage(entity Entity) {
+	text := v.app.logs.text()
+	v.handleSecondaryPageSwitch(entity, text, []byte(text))
+	v.handleHeaderPageSwitch(entity)
+}
+
+// Show time range and log stream form of entity log page, returnTo gets focus when the form is closed.
+// Log streams of a service load in background before the form is shown.
+func (v *view) showLogRangeForm(entity Entity, returnTo tview.Primitive) {
+	p := v.app.logs
+	if p == nil || p.key != entity.entityName {
+		return
+	}
+	if entity.service == nil {
+		v.showLogRangeStreamsForm(entity, p, nil, returnTo)
+		return
+	}
+
+	// services read any log stream of their log groups
+	tdArn, _, _ := v.logScope(entity)
+	store := v.app.Store
+	var streams []api.LogStream
+	v.app.loadSecondaryPage(func(ctx context.Context) (err error) {
+		streams, err = store.GetServiceLogStreams(ctx, tdArn)
+		return err
+	}, func(err error) error {
+		if err != nil {
+			v.app.Notice.Warnf("failed to list log streams, err: %v", err)
+		}
+		v.showLogRangeStreamsForm(entity, p, streams, returnTo)
+		return nil
+	})
+}
+
+// Show time range form with log streams of a service to choose from
+func (v *view) showLogRangeStreamsForm(entity Entity, p *logPager, streams []api.LogStream, returnTo tview.Primitive) {
+	title := fmt.Sprintf(" Logs of [%s::b]%s[-:-:-] ", theme.Magenta, entity.entityName)
+	f := ui.StyledForm(title)
+	rangeLabel := "Time range"
+	untilLabel := "Until"
+	streamLabel := "Log stream"
+
+	ranges := []string{}
+	for _, r := range logRanges {
+		ranges = append(ranges, r.name)
+	}
+	until := p.until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	f.AddDropDown(rangeLabel, ranges, p.rangeIndex, nil)
+	f.AddInputField(untilLabel, until.Local().Format(time.DateTime), 50, nil, nil)
+
+	if entity.service != nil {
+		options := []string{latestLogStreams}
+		selected := 0
+		for i, s := range streams {
+			options = append(options, fmt.Sprintf("%s %s (%s)", s.Group, s.Name, utils.Age(&s.LastEventTime)))
+			if p.stream != nil && *p.stream == s {
+				selected = i + 1
+			}
+		}
+		f.AddDropDown(streamLabel, options, selected, nil)
+	}
+
+	closeForm := func() {
+		v.handleFullScreenContentDone()
+		v.app.SetFocus(returnTo)
+	}
+	f.AddButton("Cancel", closeForm)
+	f.AddButton("Show", func() {
+		shown := *p
+		rangeIndex, _ := f.GetFormItemByLabel(rangeLabel).(*tview.DropDown).GetCurrentOption()
+		if rangeIndex == logRangeUntil {
+			text := strings.TrimSpace(f.GetFormItemByLabel(untilLabel).(*tview.InputField).GetText())
+			t, err := parseLogTimestamp(text)
+			if err != nil {
+				v.app.Notice.Warnf("invalid timestamp %q, use %q or RFC3339", text, time.DateTime)
+				return
+			}
+			p.until = t
+		}
+		p.rangeIndex = rangeIndex
+		if entity.service != nil {
+			p.stream = nil
+			if i, _ := f.GetFormItemByLabel(streamLabel).(*tview.DropDown).GetCurrentOption(); i > 0 {
+				p.stream = &streams[i-1]
+			}
+		}
+
+		v.handleFullScreenContentDone()
+		// the page on screen keeps its range and log stream when the load fails or is cancelled
+		v.app.loadFailed = func() {
+			p.rangeIndex, p.until, p.stream = shown.rangeIndex, shown.until, shown.stream
+		}
+		v.loadLogs(p, entity)
+	})
+
+	v.app.Pages.AddPage(title, ui.Modal(f, 100, 13, 0, closeForm), true, true)
+}
+
+// Parse local date time or RFC3339 timestamp
+func parseLogTimestamp(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateTime, s, time.Local)
+}
+
+// Task logs of several containers are merged by timestamp
+func (p *logPager) merged() bool {
+	if len(p.pages) < 2 {
+		return false
+	}
+	return !slices.ContainsFunc(p.pages, func(page *api.LogStreamPage) bool { return page.Container == "" })
+}
+
+// Toggle container of merged task logs by its index and show the log page again
+func (v *view) toggleLogContainer(entity Entity, index int) {
+	p := v.app.logs
+	if p == nil || p.key != entity.entityName || !p.merged() || index >= len(p.pages) {
+		return
+	}
+	container := p.pages[index].Container
+	if p.hidden == nil {
+		p.hidden = map[string]bool{}
+	}
+	p.hidden[container] = !p.hidden[container]
+	if p.hidden[container] {
+		v.app.Notice.Infof("Hide %s logs", container)
+	} else {
+		v.app.Notice.Infof("Show %s logs", container)
+	}
+	v.showLogPage(entity)
+}
+
+// Log page content, a section of events per log stream or merged events of task containers
+func (p *logPager) text() string {
+	var b strings.Builder
+	p.jsonLines = nil
+	p.selectedRow = 0
+
+	rangeText := logRanges[p.rangeIndex].name
+	if p.rangeIndex == logRangeUntil {
+		rangeText = "until " + p.r.Until.Local().Format(time.RFC3339)
+	}
+	switch {
+	case p.stream != nil:
+		rangeText += ", log stream " + p.stream.Name
+	case p.service:
+		rangeText += " of each task, press t to choose another log stream"
+	}
+	b.WriteString(fmt.Sprintf(color.GrayFmt+"\n", "Showing "+rangeText))
+
+	if p.merged() {
+		p.writeMerged(&b)
+		return b.String()
+	}
+
+	events := 0
+	for _, page := range p.pages {
+		if len(page.Events) == 0 {
+			continue
+		}
+		events += len(page.Events)
+		b.WriteString(fmt.Sprintf(logStreamFmt, page.Name))
+		if page.BackwardToken == nil {
+			b.WriteString(fmt.Sprintf(color.GrayFmt+"\n", "(no older events)"))
+		}
+		for _, e := range page.Events {
+			timestamp := time.UnixMilli(*e.Timestamp).Format(time.RFC3339)
+			p.writeEvent(&b, page, e, func(message string) string {
+				return fmt.Sprintf(logFmt, timestamp, message)
+			})
+		}
+	}
+	if events == 0 {
+		b.WriteString("[orange::]Empty logs[-:-:-]")
+	}
+	return b.String()
+}
+
+// Events of visible containers in timestamp order, each line prefixed with its colored container name
+func (p *logPager) writeMerged(b *strings.Builder) {
+	colors := []string{theme.Magenta, theme.Green, theme.Yellow, theme.Blue, theme.Cyan, theme.Red}
+
+	type line struct {
+		page   *api.LogStreamPage
+		event  cloudwatchlogsTypes.OutputLogEvent
+		prefix string
+	}
+	lines := []line{}
+	width := 0
+	for _, page := range p.pages {
+		width = max(width, utf8.RuneCountInString(page.Container))
+	}
+	b.WriteString("Containers:")
+	for i, page := range p.pages {
+		c := colors[i%len(colors)]
+		state := ""
+		if p.hidden[page.Container] {
+			state = " (hidden)"
+		}
+		if page.BackwardToken == nil && len(page.Events) > 0 {
+			state += " (no older events)"
+		}
+		b.WriteString(fmt.Sprintf(" %d:[%s::b]%s[-:-:-]%s", i+1, c, page.Container, state))
+		if p.hidden[page.Container] {
+			continue
+		}
+		prefix := fmt.Sprintf("[%s::b]%-*s[-:-:-]", c, width, page.Container)
+		for _, e := range page.Events {
+			lines = append(lines, line{page: page, event: e, prefix: prefix})
+		}
+	}
+	b.WriteString("\n")
+
+	slices.SortStableFunc(lines, func(a, b line) int {
+		return cmp.Compare(*a.event.Timestamp, *b.event.Timestamp)
+	})
+	for _, l := range lines {
+		timestamp := time.UnixMilli(*l.event.Timestamp).Format(time.RFC3339)
+		p.writeEvent(b, l.page, l.event, func(message string) string {
+			return fmt.Sprintf(mergedLogFmt, timestamp, l.prefix, message)
+		})
+	}
+	if len(lines) == 0 {
+		b.WriteString("[orange::]Empty logs[-:-:-]")
+	}
+}
+
+// Write formatted event with line, JSON events are selectable and expanded into indented JSON
+func (p *logPager) writeEvent(b *strings.Builder, page *api.LogStreamPage, e cloudwatchlogsTypes.OutputLogEvent, line func(message string) string) {
+	message := aws.ToString(e.Message)
+	pretty, isJSON := p.format.pretty(message)
+	if !isJSON {
+		b.WriteString(line(p.format.format(message)))
+		return
+	}
+
+	key := fmt.Sprintf("%s/%d/%d", page.Name, aws.ToInt64(e.Timestamp), aws.ToInt64(e.IngestionTime))
+	p.jsonLines = append(p.jsonLines, key)
+	if key == p.selected {
+		p.selectedRow = strings.Count(b.String(), "\n")
+		b.WriteString(fmt.Sprintf(color.YellowFmt, logLineMarker))
+	}
+	b.WriteString(line(p.format.format(message)))
+	if p.expanded[key] {
+		for _, l := range strings.Split(pretty, "\n") {
+			b.WriteString(logExpandIndent + l + "\n")
+		}
+	}
+}
+
+// Select next or previous JSON line by delta and show the log page again, false without JSON lines
+func (v *view) selectLogLine(entity Entity, delta int) bool {
+	p := v.app.logs
+	if p == nil || p.key != entity.entityName || len(p.jsonLines) == 0 {
+		return false
+	}
+	i := slices.Index(p.jsonLines, p.selected)
+	switch {
+	case i < 0 && delta < 0:
+		i = len(p.jsonLines) - 1
+	case i < 0:
+		i = 0
+	default:
+		i = (i + delta + len(p.jsonLines)) % len(p.jsonLines)
+	}
+	p.selected = p.jsonLines[i]
+	v.showSelectedLogLine(entity)
+	return true
+}
+
+// Toggle pretty JSON of selected line, false when no line is selected
+func (v *view) toggleLogLine(entity Entity) bool {
+	p := v.app.logs
+	if p == nil || p.key != entity.entityName || !slices.Contains(p.jsonLines, p.selected) {
+		return false
+	}
+	if p.expanded == nil {
+		p.expanded = map[string]bool{}
+	}
+	p.expanded[p.selected] = !p.expanded[p.selected]
+	v.showSelectedLogLine(entity)
+	return true
+}
+
+// Show log page again scrolled to selected line
+func (v *view) showSelectedLogLine(entity Entity) {
+	v.showLogPage(entity)
+	if _, item := v.tablePages.GetFrontPage(); item != nil {
+		if page, ok := item.(*textPage); ok {
+			page.text.ScrollTo(v.app.logs.selectedRow, 0)
+		}
+	}
+}
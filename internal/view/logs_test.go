@@ -0,0 +1,259 @@
+package view
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
+	"github.com/keidarcy/e1s/internal/color"
+)
+
+// 60 events of task1, one per second from logsBase
+const logsBase = 1790000000000
+
+func logsSnapshot() string {
+	var b strings.Builder
+	b.WriteString(`
+TaskDefinitions:
+  - TaskDefinitionArn: arn:aws:ecs:us-east-1:111111:task-definition/app:1
+    ContainerDefinitions:
+      - Name: app
+        LogConfiguration:
+          LogDriver: awslogs
+          Options:
+            awslogs-group: /ecs/app
+            awslogs-stream-prefix: ecs
+Logs:
+  /ecs/app:
+    ecs/app/task1:
+`)
+	for i := 1; i <= 60; i++ {
+		fmt.Fprintf(&b, "      - Timestamp: %d\n        Message: event %02d\n", logsBase+int64(i)*1000, i)
+	}
+	return b.String()
+}
+
+// Log page of entity as the logs key shows it, the load is synchronous before the app runs
+func showLogsText(v *view, entity Entity) string {
+	v.showLogs(entity)
+	return v.app.logs.text()
+}
+
+func TestLogPages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.yml")
+	if err := os.WriteFile(path, []byte(logsSnapshot()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	app, err := newApp(Option{Fixtures: path, Refresh: -1})
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+	app.kind = TaskKind
+	app.secondaryKind = LogKind
+	task := types.Task{
+		TaskArn:           aws.String("arn:aws:ecs:us-east-1:111111:task/cluster/task1"),
+		TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:111111:task-definition/app:1"),
+	}
+	v := newTaskView([]types.Task{task}, false, app)
+	entity := Entity{task: &task, entityName: *task.TaskArn}
+
+	text := showLogsText(&v.view, entity)
+	if !strings.Contains(text, "event 60") || !strings.Contains(text, "event 11") || strings.Contains(text, "event 10") {
+		t.Fatalf("Want: latest 50 events, Got:\n%s", text)
+	}
+
+	v.loadOlderLogs(entity)
+	if text := app.logs.text(); !strings.Contains(text, "event 01") || strings.Count(text, "event ") != 60 {
+		t.Errorf("Want: older events loaded to the start of the stream, Got:\n%s", text)
+	}
+	v.loadOlderLogs(entity)
+	if page := app.logs.pages[0]; page.BackwardToken != nil || len(page.Events) != 60 {
+		t.Errorf("Got %d events, Want: no older events after the start of the stream", len(page.Events))
+	}
+
+	app.logs.rangeIndex = logRangeUntil
+	app.logs.until = time.UnixMilli(logsBase + 30*1000)
+	text = showLogsText(&v.view, entity)
+	if !strings.Contains(text, "event 29") || strings.Contains(text, "event 30") {
+		t.Errorf("Want: events before the timestamp, Got:\n%s", text)
+	}
+	if app.logs.rangeIndex != logRangeUntil {
+		t.Error("Want: time range kept when the log page is reloaded")
+	}
+}
+
+func TestLogStreamFailuresWarned(t *testing.T) {
+	// sidecar writes to a log stream missing in its log group
+	snapshot := strings.Replace(logsSnapshot(), "ContainerDefinitions:\n", `ContainerDefinitions:
+      - Name: sidecar
+        LogConfiguration:
+          LogDriver: awslogs
+          Options:
+            awslogs-group: /ecs/app
+            awslogs-stream-prefix: ecs
+`, 1)
+	path := filepath.Join(t.TempDir(), "snapshot.yml")
+	if err := os.WriteFile(path, []byte(snapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	app := demoApp(t, Option{Fixtures: path})
+	app.kind = TaskKind
+	app.secondaryKind = LogKind
+	task := types.Task{
+		TaskArn:           aws.String("arn:aws:ecs:us-east-1:111111:task/cluster/task1"),
+		TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:111111:task-definition/app:1"),
+	}
+	v := newTaskView([]types.Task{task}, false, app)
+	entity := Entity{task: &task, entityName: *task.TaskArn}
+	runTestApp(t, app)
+
+	app.QueueUpdate(func() {
+		v.showLogs(entity)
+	})
+	waitFor(t, app, func() bool {
+		return app.load == nil && strings.Contains(app.Notice.GetText(true), "failed to load log streams ecs/sidecar/task1")
+	})
+	app.QueueUpdate(func() {
+		if text := app.logs.text(); !strings.Contains(text, "event 60") {
+			t.Errorf("Want: events of the log stream loaded, Got:\n%s", text)
+		}
+	})
+}
+
+func TestServiceLogPage(t *testing.T) {
+	app := demoApp(t, Option{})
+	app.kind = ServiceKind
+	app.secondaryKind = LogKind
+	service := types.Service{
+		ServiceName:    aws.String("web"),
+		TaskDefinition: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+	}
+	app.cluster = &types.Cluster{ClusterName: aws.String("demo-production")}
+	v := newServiceView([]types.Service{service}, app)
+	entity := Entity{service: &service, entityName: "web"}
+
+	// latest log stream of each running task merged
+	text := showLogsText(&v.view, entity)
+	if !app.logs.merged() || !strings.Contains(text, "web/0a1b2c3d") || !strings.Contains(text, "web/1b2c3d4e") || strings.Contains(text, "envoy") {
+		t.Errorf("Want: latest log stream of both tasks, Got:\n%s", text)
+	}
+	if !strings.Contains(text, "press t to choose another log stream") {
+		t.Errorf("Want: hint of the log stream picker, Got:\n%s", text)
+	}
+
+	streams, err := app.Store.GetServiceLogStreams(t.Context(), service.TaskDefinition)
+	if err != nil || len(streams) != 3 {
+		t.Fatalf("Got %d log streams, err %v, Want: 3", len(streams), err)
+	}
+	app.logs.stream = &streams[2]
+	if text := showLogsText(&v.view, entity); !strings.Contains(text, streams[2].Name) || strings.Contains(text, "ecs/web/1b2c3d4e5f60718293a4b5c6d7e8f90a") {
+		t.Errorf("Want: selected log stream %s, Got:\n%s", streams[2].Name, text)
+	}
+}
+
+func TestParseLogTimestamp(t *testing.T) {
+	want := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	for _, s := range []string{"2026-10-01T12:30:00Z", want.Local().Format(time.DateTime)} {
+		got, err := parseLogTimestamp(s)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseLogTimestamp(%q) = %v, %v, Want: %v", s, got, err, want)
+		}
+	}
+	if _, err := parseLogTimestamp("yesterday"); err == nil {
+		t.Error("Want: error of invalid timestamp")
+	}
+}
+
+func TestMergedTaskLogs(t *testing.T) {
+	theme = color.InitStyles("")
+	app := demoApp(t, Option{})
+	app.kind = TaskKind
+	app.secondaryKind = LogKind
+	task := types.Task{
+		TaskArn:           aws.String("arn:aws:ecs:us-east-1:123456789012:task/demo-production/0a1b2c3d4e5f60718293a4b5c6d7e8f9"),
+		TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+	}
+	v := newTaskView([]types.Task{task}, false, app)
+	entity := Entity{task: &task, entityName: *task.TaskArn}
+
+	text := showLogsText(&v.view, entity)
+	if !app.logs.merged() || strings.Contains(text, "Log stream name") {
+		t.Fatalf("Want: containers merged, Got:\n%s", text)
+	}
+	// one line per event in timestamp order
+	lines := strings.Split(strings.TrimSpace(text), "\n")[2:]
+	if len(lines) != 6 {
+		t.Fatalf("Got %d lines, Want: 6 events of web and envoy", len(lines))
+	}
+	if !strings.Contains(lines[0], "envoy") || !strings.Contains(lines[1], "web") || !sortedLines(lines) {
+		t.Errorf("Want: events of both containers interleaved by timestamp, Got:\n%s", text)
+	}
+
+	envoy := slices.IndexFunc(app.logs.pages, func(p *api.LogStreamPage) bool { return p.Container == "envoy" })
+	v.toggleLogContainer(entity, envoy)
+	text = app.logs.text()
+	if strings.Count(text, "\n") != 2+4 || !strings.Contains(text, "envoy[-:-:-] (hidden)") {
+		t.Errorf("Want: envoy left out of the merge, Got:\n%s", text)
+	}
+	if text = showLogsText(&v.view, entity); !app.logs.hidden["envoy"] {
+		t.Error("Want: hidden containers kept when the log page is reloaded")
+	}
+	v.toggleLogContainer(entity, envoy)
+	if strings.Count(app.logs.text(), "\n") != 2+6 {
+		t.Error("Want: envoy merged again")
+	}
+}
+
+// Lines start with RFC3339 timestamps after color tags
+func sortedLines(lines []string) bool {
+	return slices.IsSortedFunc(lines, func(a, b string) int {
+		return strings.Compare(colorTagRegexp.ReplaceAllString(a, "")[:20], colorTagRegexp.ReplaceAllString(b, "")[:20])
+	})
+}
+
+func TestLogLineExpand(t *testing.T) {
+	app := demoApp(t, Option{})
+	app.kind = ServiceKind
+	app.secondaryKind = LogKind
+	service := types.Service{
+		ServiceName:    aws.String("web"),
+		TaskDefinition: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+	}
+	v := newServiceView([]types.Service{service}, app)
+	entity := Entity{service: &service, entityName: "web"}
+
+	text := showLogsText(&v.view, entity)
+	if !strings.Contains(text, "failed to render page") || strings.Contains(text, `"msg"`) {
+		t.Fatalf("Want: JSON lines rendered as fields, Got:\n%s", text)
+	}
+	if v.toggleLogLine(entity) {
+		t.Error("Want: nothing expanded before a line is selected")
+	}
+
+	// select the last JSON line
+	if !v.selectLogLine(entity, -1) {
+		t.Fatal("Want: JSON line selected")
+	}
+	if !v.toggleLogLine(entity) {
+		t.Fatal("Want: selected line expanded")
+	}
+	text = app.logs.text()
+	if !strings.Contains(text, logLineMarker) || !strings.Contains(text, logExpandIndent+`  "error": "context deadline exceeded"`) {
+		t.Errorf("Want: selected line expanded into indented JSON, Got:\n%s", text)
+	}
+	if rows := strings.Split(text, "\n"); !strings.Contains(rows[app.logs.selectedRow], logLineMarker) {
+		t.Errorf("Got row %d, Want: row of the selected line", app.logs.selectedRow)
+	}
+
+	v.toggleLogLine(entity)
+	if strings.Contains(app.logs.text(), logExpandIndent) {
+		t.Error("Want: expanded line collapsed")
+	}
+}
@@ -0,0 +1,42 @@
+package view
+
+import "slices"
+
+// Marks of regions and profiles whose clusters are listed together
+
+func markText(marks []string, name string) string {
+	if slices.Contains(marks, name) {
+		return "✓"
+	}
+	return ""
+}
+
+// Mark or unmark selected row, name gives the marked name of a row
+func (v *view) toggleMark(marks *[]string, column int, name func(Entity) string) {
+	selected, err := v.getCurrentSelection()
+	if err != nil || name(selected) == "" {
+		return
+	}
+	n := name(selected)
+	if i := slices.Index(*marks, n); i >= 0 {
+		*marks = slices.Delete(*marks, i, i+1)
+	} else {
+		*marks = append(*marks, n)
+	}
+	v.showMarks(*marks, column, name)
+}
+
+// Sync mark column with marks
+func (v *view) showMarks(marks []string, column int, name func(Entity) string) {
+	for i, ref := range v.originalRowReferences {
+		if n := name(ref); n != "" && column < len(v.originalRowData[i]) {
+			v.originalRowData[i][column] = markText(marks, n)
+		}
+	}
+	for row := 1; row < v.table.GetRowCount(); row++ {
+		cell := v.table.GetCell(row, column)
+		if ref, ok := cell.GetReference().(Entity); ok && name(ref) != "" {
+			cell.SetText(markText(marks, name(ref)))
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package view
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keidarcy/e1s/internal/api"
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/keidarcy/e1s/internal/utils"
+)
+
+const (
+	// sparkline width in metrics page
+	metricsChartWidth = 60
+	// utilization thresholds of warning and high colors
+	utilizationWarn = 70.0
+	utilizationHigh = 90.0
+	// utilization cell text while metrics load
+	utilizationPending = "…"
+)
+
+// Switch to selected service metrics page
+func (v *view) switchToMetrics() {
+	v.showMetrics(v.app.metricsWindow)
+}
+
+// Select metrics window by index and reload metrics page
+func (v *view) selectMetricsWindow(index int) {
+	if index < 0 || index >= len(api.MetricsWindows) || index == v.app.metricsWindow {
+		return
+	}
+	v.showMetrics(index)
+}
+
+// Load metrics of window in background and show them in the metrics page,
+// the window is selected once its metrics are shown
+func (v *view) showMetrics(window int) {
+	selected, err := v.getCurrentSelection()
+	if err != nil {
+		v.app.Notice.Warnf("failed to switchToMetrics")
+		return
+	}
+	if v.app.kind != ServiceKind {
+		return
+	}
+
+	shown := v.secondaryPageShown(selected)
+	store, cluster, service := v.app.Store, v.app.cluster.ClusterName, selected.service.ServiceName
+	var metrics *api.ServiceMetrics
+	v.loadSecondaryContent(selected, func(ctx context.Context) (err error) {
+		metrics, err = store.GetServiceMetrics(ctx, cluster, service, api.MetricsWindows[window])
+		return err
+	}, func(err error) error {
+		if err != nil {
+			v.app.Notice.Warnf("failed to get service metrics, err: %v", err)
+			return err
+		}
+		jsonBytes, err := json.MarshalIndent(metrics, "", "  ")
+		if err != nil {
+			v.app.Notice.Warnf("failed to marshal service metrics, err: %v", err)
+			return err
+		}
+		changed := window != v.app.metricsWindow
+		v.app.metricsWindow = window
+		v.handleSecondaryPageSwitch(selected, metricsText(metrics, window), jsonBytes)
+		v.handleHeaderPageSwitch(selected)
+		if shown && changed {
+			v.app.Notice.Infof("Viewing metrics of last %s", api.MetricsWindows[window].Name)
+		}
+		return nil
+	})
+}
+
+// Content of metrics page, a sparkline per metric with its latest value
+func metricsText(metrics *api.ServiceMetrics, selected int) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf(color.MetricsLabelFmt, "Window"))
+	for i, w := range api.MetricsWindows {
+		item := fmt.Sprintf("%d:%s", i+1, w.Name)
+		if i == selected {
+			b.WriteString(fmt.Sprintf(color.MetricsWindowSelectedFmt, item))
+		} else {
+			b.WriteString(fmt.Sprintf(color.MetricsWindowFmt, item))
+		}
+	}
+	b.WriteString(fmt.Sprintf(color.GrayFmt, fmt.Sprintf("  period %ds", metrics.Window.Period)))
+	b.WriteString("\n\n")
+
+	percent := func(f float64) string {
+		return fmt.Sprintf(utilizationFmt(f), fmt.Sprintf("%6.2f%%", f))
+	}
+	count := func(f float64) string {
+		return fmt.Sprintf("%6.0f", f)
+	}
+	rows := []struct {
+		label  string
+		series api.MetricSeries
+		top    float64
+		value  func(float64) string
+		// shown when series is empty
+		hint string
+	}{
+		{"CPU average", metrics.CPUAverage, 100, percent, ""},
+		{"CPU maximum", metrics.CPUMaximum, 100, percent, ""},
+		{"Memory average", metrics.MemoryAverage, 100, percent, ""},
+		{"Memory maximum", metrics.MemoryMaximum, 100, percent, ""},
+		{"Running tasks", metrics.RunningTaskCount, 0, count, " (requires Container Insights)"},
+	}
+	for _, r := range rows {
+		b.WriteString(fmt.Sprintf(color.MetricsLabelFmt, r.label))
+		last, ok := r.series.Last()
+		if !ok {
+			b.WriteString(fmt.Sprintf(color.GrayFmt, utils.EmptyText+r.hint))
+			b.WriteString("\n")
+			continue
+		}
+		spark := utils.Sparkline(r.series.Values, r.top, metricsChartWidth)
+		b.WriteString(spark)
+		b.WriteString(strings.Repeat(" ", metricsChartWidth-utf8.RuneCountInString(spark)+1))
+		b.WriteString(r.value(last))
+		b.WriteString("\n")
+	}
+
+	if len(metrics.CPUAverage.Timestamps) > 0 {
+		first := metrics.CPUAverage.Timestamps[0]
+		last := metrics.CPUAverage.Timestamps[len(metrics.CPUAverage.Timestamps)-1]
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf(color.MetricsLabelFmt, ""))
+		b.WriteString(fmt.Sprintf(color.GrayFmt, fmt.Sprintf("%s ~ %s", first.Local().Format(time.DateTime), last.Local().Format(time.DateTime))))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Color format of a utilization percentage against thresholds
+func utilizationFmt(f float64) string {
+	switch {
+	case f >= utilizationHigh:
+		return color.RedFmt
+	case f >= utilizationWarn:
+		return color.YellowFmt
+	default:
+		return color.GreenFmt
+	}
+}
+
+// Colored utilization percentage of table cell
+func utilizationText(f *float64) string {
+	if f == nil {
+		return utils.EmptyText
+	}
+	return fmt.Sprintf(utilizationFmt(*f), fmt.Sprintf("%.1f%%", *f))
+}
+
+// CPU% and Mem% cells of key, pending until its utilization is loaded
+func utilizationCells(utilization map[string]api.Utilization, key string) []string {
+	u, ok := utilization[key]
+	if !ok {
+		return []string{utilizationPending, utilizationPending}
+	}
+	return []string{utilizationText(u.CPU), utilizationText(u.Memory)}
+}
+
+// Set utilization cells of table rows and row data for sort and filter
+func (v *view) fillUtilization(utilization func(entity Entity) api.Utilization) {
+	cpuColumn := slices.Index(v.headers, "CPU%")
+	memColumn := slices.Index(v.headers, "Mem%")
+	if cpuColumn < 0 || memColumn < 0 {
+		return
+	}
+	for i, row := range v.originalRowData {
+		u := utilization(v.originalRowReferences[i])
+		row[cpuColumn] = utilizationText(u.CPU)
+		row[memColumn] = utilizationText(u.Memory)
+	}
+	for y := 1; y < v.table.GetRowCount(); y++ {
+		entity, ok := v.table.GetCell(y, 0).GetReference().(Entity)
+		if !ok {
+			continue
+		}
+		u := utilization(entity)
+		v.table.GetCell(y, cpuColumn).SetText(utilizationText(u.CPU))
+		v.table.GetCell(y, memColumn).SetText(utilizationText(u.Memory))
+	}
+	// rows sorted by utilization are sorted again with the values
+	if v.sortColumn == cpuColumn || v.sortColumn == memColumn {
+		v.rebuildTableFromOriginalIndexes(v.getSortedOriginalIndexWithFilterText(v.sortColumn))
+	}
+}
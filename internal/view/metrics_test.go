@@ -0,0 +1,60 @@
+package view
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/keidarcy/e1s/internal/api"
+)
+
+func TestMetricsText(t *testing.T) {
+	app := demoApp(t, Option{})
+	metrics, err := app.Store.GetServiceMetrics(context.Background(), aws.String("demo-production"), aws.String("web"), api.MetricsWindows[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := metricsText(metrics, 1)
+	for _, want := range []string{"2:6h", "period 300s", "CPU maximum", "Running tasks", "█"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("metrics page missing %q:\n%s", want, text)
+		}
+	}
+
+	metrics.RunningTaskCount = api.MetricSeries{}
+	if text := metricsText(metrics, 1); !strings.Contains(text, "requires Container Insights") {
+		t.Errorf("metrics page missing Container Insights hint:\n%s", text)
+	}
+}
+
+func TestServiceMetricsPage(t *testing.T) {
+	v := demoServiceView(t, Option{})
+	app := v.app
+	runTestApp(t, app)
+
+	metricsPage := func() string {
+		name, item := v.tablePages.GetFrontPage()
+		if !strings.Contains(name, "."+MetricsKind.String()+".") {
+			return ""
+		}
+		return item.(*textPage).content
+	}
+	app.QueueUpdate(func() {
+		app.secondaryKind = MetricsKind
+		v.switchToMetrics()
+	})
+	waitFor(t, app, func() bool { return app.load == nil && strings.Contains(metricsPage(), "1:1h") })
+
+	// window is selected once its metrics are shown
+	app.QueueUpdate(func() {
+		v.selectMetricsWindow(2)
+	})
+	waitFor(t, app, func() bool { return app.load == nil && app.metricsWindow == 2 })
+	app.QueueUpdate(func() {
+		if !strings.Contains(metricsPage(), "period 900s") {
+			t.Errorf("Got metrics page:\n%s\nWant: 24h window", metricsPage())
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -107,7 +108,7 @@ func (v *view) stopTaskForm() (*tview.Form, *string) {
 			Task:    aws.String(taskId),
 			Cluster: aws.String(clusterName),
 		}
-		err := v.app.Store.StopTask(input)
+		err := v.app.Store.StopTask(context.Background(), input)
 
 		if err != nil {
 			v.app.Notice.Error(err.Error())
@@ -149,7 +150,7 @@ func (v *view) rollbackServiceDeploymentForm() (*tview.Form, *string) {
 
 	// handle form submit
 	f.AddButton("Rollback", func() {
-		err := v.app.Store.RollbackServiceDeployment(v.app.serviceDeployment.ServiceDeploymentArn)
+		err := v.app.Store.RollbackServiceDeployment(context.Background(), v.app.serviceDeployment.ServiceDeploymentArn)
 
 		if err != nil {
 			v.app.Notice.Error(err.Error())
@@ -197,7 +198,7 @@ func (v *view) serviceUpdateWithSpecificTaskDefinitionForm() (*tview.Form, *stri
 			Cluster:        v.app.cluster.ClusterName,
 			TaskDefinition: aws.String(td),
 		}
-		s, err := v.app.Store.UpdateService(input)
+		s, err := v.app.Store.UpdateService(context.Background(), input)
 
 		if err != nil {
 			v.app.Notice.Error(err.Error())
@@ -258,7 +259,7 @@ func (v *view) serviceUpdateForm() (*tview.Form, *string) {
 				return
 			}
 
-			taskDefinitions, err := v.app.Store.ListTaskDefinition(&familyName)
+			taskDefinitions, err := v.app.Store.ListTaskDefinition(context.Background(), &familyName)
 			if err != nil {
 				v.app.Notice.Errorf("Failed list task definition, err: %s", err.Error())
 				v.closeModal()
@@ -317,7 +318,7 @@ func (v *view) serviceUpdateForm() (*tview.Form, *string) {
 				familyPrefix = aws.String(trimmedPrefix)
 			}
 
-			families, err := v.app.Store.ListTaskDefinitionFamilies(familyPrefix)
+			families, err := v.app.Store.ListTaskDefinitionFamilies(context.Background(), familyPrefix)
 			if err != nil {
 				v.app.Notice.Errorf("failed list task definition families, err: %s", err.Error())
 				v.closeModal()
@@ -413,7 +414,7 @@ func (v *view) serviceUpdateForm() (*tview.Form, *string) {
 				ForceNewDeployment:   force,
 				EnableExecuteCommand: &execCommand,
 			}
-			s, err = v.app.Store.UpdateService(input)
+			s, err = v.app.Store.UpdateService(context.Background(), input)
 		} else {
 			input = &ecs.UpdateServiceInput{
 				Service:              aws.String(name),
@@ -422,7 +423,7 @@ func (v *view) serviceUpdateForm() (*tview.Form, *string) {
 				ForceNewDeployment:   force,
 				EnableExecuteCommand: &execCommand,
 			}
-			s, err = v.app.Store.UpdateService(input)
+			s, err = v.app.Store.UpdateService(context.Background(), input)
 		}
 
 		if err != nil {
@@ -433,21 +434,14 @@ func (v *view) serviceUpdateForm() (*tview.Form, *string) {
 		} else {
 			v.closeModal()
 
-			// go v.app.QueueUpdateDraw(func() {
-			// 	cell := v.table.GetCell(row, 3)
-			// 	cell.SetText(strings.Replace(cell.Text,  "[green]completed[-:-:-]", "[grey]in_progress[-:-:-]", 1))
-			// })
-
-			// Update service last deployment
-			go func() {
-				row, _ := v.table.GetSelection()
-				if row == 0 {
-					row++
-				}
-				cell := v.table.GetCell(row, 4)
-				cell.SetText(strings.Replace(cell.Text, "[green]Completed[-:-:-]", "[grey]In_progress[-:-:-]", 1))
-				v.app.Application.Draw()
-			}()
+			// Update service last deployment until the reload shows the new one.
+			// Form callbacks run on the main loop, the cell can be changed directly.
+			row, _ := v.table.GetSelection()
+			if row == 0 {
+				row++
+			}
+			cell := v.table.GetCell(row, 4)
+			cell.SetText(strings.Replace(cell.Text, "[green]Completed[-:-:-]", "[grey]In_progress[-:-:-]", 1))
 
 			v.app.Notice.Infof("update service:\"%s\" with \"%d\" task definition:\"%s\" task(s)", *s.ServiceName, s.DesiredCount, utils.ArnToName(s.TaskDefinition))
 			v.reloadResource(false)
@@ -455,40 +449,3 @@ func (v *view) serviceUpdateForm() (*tview.Form, *string) {
 	})
 	return f, &title
 }
-
-// Get service metrics charts
-func (v *view) serviceMetricsForm() (*tview.Form, *string) {
-	if v.app.kind != ServiceKind {
-		return nil, nil
-	}
-
-	selected, err := v.getCurrentSelection()
-	if err != nil {
-		return nil, nil
-	}
-	cluster := v.app.cluster.ClusterName
-	service := *selected.service.ServiceName
-
-	title := " Metrics [purple::b](" + service + ")" + readOnlyLabel
-
-	f := ui.StyledForm(title)
-	f.AddInputField("Service ", service+placeholder, len(service)+len(placeholder)+1, nil, nil)
-
-	metrics, err := v.app.Store.GetMetrics(cluster, &service)
-
-	// empty Metrics or empty
-	if err != nil || (len(metrics.CPUUtilization) == 0 && len(metrics.MemoryUtilization) == 0) {
-		return f, &title
-	}
-	if len(metrics.CPUUtilization) > 0 {
-		cpuLabel := "CPUUtilization"
-		f.AddTextView(cpuLabel, utils.BuildMeterText(*metrics.CPUUtilization[0].Average), 50, 1, true, false)
-	}
-
-	if len(metrics.MemoryUtilization) > 0 {
-		memLabel := "MemoryUtilization"
-		f.AddTextView(memLabel, utils.BuildMeterText(*metrics.MemoryUtilization[0].Average), 50, 1, true, false)
-	}
-
-	return f, &title
-}
@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -30,7 +31,7 @@ func (v *view) portForwardingForm() (*tview.Form, *string) {
 	placeHolderPort := "8080"
 	placeHolderLocalPort := "8080"
 
-	td, err := v.app.Store.DescribeTaskDefinition(v.app.task.TaskDefinitionArn)
+	td, err := v.app.Store.DescribeTaskDefinition(context.Background(), v.app.task.TaskDefinitionArn)
 	if err != nil {
 		return nil, nil
 	}
@@ -87,7 +88,7 @@ func (v *view) portForwardingForm() (*tview.Form, *string) {
 		port := f.GetFormItemByLabel(portLabel).(*tview.InputField).GetText()
 		localPort := f.GetFormItemByLabel(localPortLabel).(*tview.InputField).GetText()
 
-		sessionId, err := v.app.Store.StartSession(&api.SsmStartSessionInput{
+		sessionId, err := v.app.Store.StartSession(context.Background(), &api.SsmStartSessionInput{
 			ClusterName: clusterName,
 			Host:        host,
 			TaskId:      taskId,
@@ -95,6 +96,7 @@ func (v *view) portForwardingForm() (*tview.Form, *string) {
 			RemoteHost:  remoteHost,
 			Port:        port,
 			LocalPort:   localPort,
+			Partition:   utils.ArnToPartition(v.app.task.TaskArn),
 		}, globalProfile, globalRegion)
 
 		if err != nil {
@@ -113,22 +115,19 @@ func (v *view) portForwardingForm() (*tview.Form, *string) {
 
 			v.app.Notice.Infof("port forwarding session started on %s", localPort)
 
-			// Update port
-			go func() {
-				row, _ := v.table.GetSelection()
-				if row == 0 {
-					row++
-				}
-				cell := v.table.GetCell(row, 3)
-				text := cell.Text
-				if text == utils.EmptyText {
-					text = localPort
-				} else {
-					text = fmt.Sprintf("%s,%s", text, localPort)
-				}
-				cell.SetText(text)
-				v.app.Application.Draw()
-			}()
+			// Update port, form callbacks run on the main loop
+			row, _ := v.table.GetSelection()
+			if row == 0 {
+				row++
+			}
+			cell := v.table.GetCell(row, 3)
+			text := cell.Text
+			if text == utils.EmptyText {
+				text = localPort
+			} else {
+				text = fmt.Sprintf("%s,%s", text, localPort)
+			}
+			cell.SetText(text)
 
 			v.reloadResource(false)
 		}
@@ -188,23 +187,20 @@ func (v *view) terminatePortForwardingForm() (*tview.Form, *string) {
 	// handle form submit
 	f.AddButton("Terminate", func() {
 		// terminal targe container sessions
-		err := v.app.Store.TerminateSessions(sessionIds)
+		err := v.app.Store.TerminateSessions(context.Background(), sessionIds)
 		if err != nil {
 			slog.Error("failed to terminated port forwarding sessions", "error", err)
 		} else {
 			v.app.Notice.Infof("success terminated sessions on port %s", portText)
 		}
 
-		// Update port
-		go func() {
-			row, _ := v.table.GetSelection()
-			if row == 0 {
-				row++
-			}
-			cell := v.table.GetCell(row, 3)
-			cell.SetText(utils.EmptyText)
-			v.app.Application.Draw()
-		}()
+		// Update port, form callbacks run on the main loop
+		row, _ := v.table.GetSelection()
+		if row == 0 {
+			row++
+		}
+		cell := v.table.GetCell(row, 3)
+		cell.SetText(utils.EmptyText)
 
 		v.closeModal()
 
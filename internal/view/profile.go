@@ -1,7 +1,9 @@
 package view
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	"github.com/keidarcy/e1s/internal/api"
 	"github.com/keidarcy/e1s/internal/color"
@@ -14,8 +16,11 @@ type profileView struct {
 }
 
 func newProfileView(profiles []api.Profile, app *App) *profileView {
+	keys := append(tableInputs, hotKeyMap["space"])
+	// Marks start from profiles listed together now
+	app.markedProfiles = slices.Clone(globalProfiles)
 	return &profileView{
-		view: *newView(app, tableInputs, secondaryPageKeyMap{
+		view: *newView(app, keys, secondaryPageKeyMap{
 			DescriptionKind: describePageKeys,
 		}),
 		profiles: profiles,
@@ -28,12 +33,16 @@ func (app *App) showProfilesPage(reload bool) error {
 		return nil
 	}
 
-	profiles, err := app.Store.ListProfiles()
-
-	err = buildResourcePage(profiles, app, err, func() resourceViewBuilder {
-		return newProfileView(profiles, app)
+	var profiles []api.Profile
+	store := app.Store
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		profiles, err = store.ListProfiles()
+		return err
+	}, func(err error) error {
+		return buildResourcePage(profiles, app, err, func() resourceViewBuilder {
+			return newProfileView(profiles, app)
+		})
 	})
-	return err
 }
 
 func (v *profileView) getViewAndFooter() (*view, *tview.TextView) {
@@ -64,6 +73,7 @@ func (v *profileView) headerPageItems(index int) (items []headerItem) {
 		{name: "Source", value: p.Source},
 		{name: "Default region", value: region},
 		{name: "Auth style", value: p.AuthStyle},
+		{name: "Multi", value: markText(v.app.markedProfiles, p.Name)},
 	}
 	return
 }
@@ -76,6 +86,7 @@ func (v *profileView) tableParamsBuilder() (title string, headers []string, rows
 		"Source",
 		"Default region",
 		"Auth style",
+		"Multi",
 	}
 
 	rowsBuilder = func() (data [][]string) {
@@ -84,7 +95,7 @@ func (v *profileView) tableParamsBuilder() (title string, headers []string, rows
 			if region == "" {
 				region = "—"
 			}
-			row := []string{p.Name, p.Source, region, p.AuthStyle}
+			row := []string{p.Name, p.Source, region, p.AuthStyle, markText(v.app.markedProfiles, p.Name)}
 			data = append(data, row)
 			entity := Entity{profile: p.Name, entityName: p.Name}
 			v.originalRowReferences = append(v.originalRowReferences, entity)
@@ -93,3 +104,9 @@ func (v *profileView) tableParamsBuilder() (title string, headers []string, rows
 	}
 	return
 }
+
+const profileMarkColumn = 4
+
+func profileName(e Entity) string {
+	return e.profile
+}
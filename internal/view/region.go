@@ -1,7 +1,9 @@
 package view
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	"github.com/keidarcy/e1s/internal/api"
 	"github.com/keidarcy/e1s/internal/color"
@@ -14,8 +16,11 @@ type regionView struct {
 }
 
 func newRegionView(regions []api.Region, app *App) *regionView {
+	keys := append(tableInputs, hotKeyMap["space"])
+	// Marks start from regions listed together now
+	app.markedRegions = slices.Clone(globalRegions)
 	return &regionView{
-		view: *newView(app, tableInputs, secondaryPageKeyMap{
+		view: *newView(app, keys, secondaryPageKeyMap{
 			DescriptionKind: describePageKeys,
 		}),
 		regions: regions,
@@ -28,12 +33,16 @@ func (app *App) showRegionsPage(reload bool) error {
 		return nil
 	}
 
-	regions, err := app.Store.ListRegions()
-
-	err = buildResourcePage(regions, app, err, func() resourceViewBuilder {
-		return newRegionView(regions, app)
+	var regions []api.Region
+	store := app.Store
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		regions, err = store.ListRegions(ctx)
+		return err
+	}, func(err error) error {
+		return buildResourcePage(regions, app, err, func() resourceViewBuilder {
+			return newRegionView(regions, app)
+		})
 	})
-	return err
 }
 
 func (v *regionView) getViewAndFooter() (*view, *tview.TextView) {
@@ -59,6 +68,7 @@ func (v *regionView) headerPageItems(index int) (items []headerItem) {
 		{name: "Code", value: r.Code},
 		{name: "Name", value: r.Name},
 		{name: "Enabled", value: r.Enabled},
+		{name: "Multi", value: v.regionMark(r.Code)},
 	}
 	return
 }
@@ -70,6 +80,7 @@ func (v *regionView) tableParamsBuilder() (title string, headers []string, rowsB
 		"Code",
 		"Name",
 		"Enabled",
+		"Multi",
 	}
 
 	rowsBuilder = func() (data [][]string) {
@@ -78,6 +89,7 @@ func (v *regionView) tableParamsBuilder() (title string, headers []string, rowsB
 			row = append(row, r.Code)
 			row = append(row, r.Name)
 			row = append(row, r.Enabled)
+			row = append(row, v.regionMark(r.Code))
 			data = append(data, row)
 
 			entity := Entity{region: &r, entityName: r.Code}
@@ -87,3 +99,16 @@ func (v *regionView) tableParamsBuilder() (title string, headers []string, rowsB
 	}
 	return
 }
+
+const regionMarkColumn = 3
+
+func (v *view) regionMark(code string) string {
+	return markText(v.app.markedRegions, code)
+}
+
+func regionName(e Entity) string {
+	if e.region == nil {
+		return ""
+	}
+	return e.region.Code
+}
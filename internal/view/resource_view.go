@@ -30,7 +30,17 @@ func buildResourcePage[T any](
 	app *App,
 	err error,
 	newResourceViewBuilder func() resourceViewBuilder,
-) error {
+) (pageErr error) {
+	if isPartial(err) && len(resources) > 0 {
+		// Build the page with what was listed, caller warns about the rest
+		partialErr := err
+		defer func() {
+			if pageErr == nil {
+				pageErr = partialErr
+			}
+		}()
+		err = nil
+	}
 	err = resourceViewPreHandler(resources, app, err)
 	if err != nil {
 		return err
@@ -0,0 +1,247 @@
+package view
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/rivo/tview"
+)
+
+// prefix of region ids wrapping search matches
+const searchRegionPrefix = "search-"
+
+var (
+	// style or region tag, e.g. "[red::b]" or `["id"]`
+	textTagPattern = regexp.MustCompile(`^\[[^\[\]]*\]`)
+	// escaped tag, e.g. "[red[]" shown as "[red]"
+	textEscapedTagPattern = regexp.MustCompile(`^\[[^\[\]]+\[+\]`)
+)
+
+// Text page of secondary kinds with / search, n and N jump between matches
+type textPage struct {
+	*tview.Flex
+	text  *tview.TextView
+	input *tview.InputField
+	// content without search highlights
+	content string
+	title   string
+	pattern string
+	matches int
+	// index of the highlighted match
+	current int
+}
+
+func newTextPage(content string, title string) *textPage {
+	p := &textPage{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		text:    getSecondaryTextItem(content, title),
+		content: content,
+		title:   title,
+	}
+	p.AddItem(p.text, 0, 1, true)
+	return p
+}
+
+// Handle search keys of page, nil when the key is handled
+func (v *view) handleTextSearchInput(p *textPage, event *tcell.EventKey) *tcell.EventKey {
+	switch event.Rune() {
+	case '/':
+		p.showSearchInput(v.app)
+		return nil
+	case 'n':
+		if p.next(1) {
+			return nil
+		}
+	case 'N':
+		if p.next(-1) {
+			return nil
+		}
+	}
+	// esc clears the search before leaving the page
+	if event.Key() == tcell.KeyEsc && p.pattern != "" {
+		p.search("")
+		return nil
+	}
+	return event
+}
+
+// Show search input below the text, pattern is searched while typing
+func (p *textPage) showSearchInput(app *App) {
+	if p.input != nil {
+		app.SetFocus(p.input)
+		return
+	}
+	p.input = tview.NewInputField().
+		SetLabel("[gray]🔍 /[-] ").
+		SetLabelColor(color.Color(theme.Cyan)).
+		SetText(p.pattern)
+	p.input.SetBackgroundColor(color.Color(theme.BgColor))
+	p.input.SetFieldBackgroundColor(color.Color(theme.BgColor))
+	p.input.SetFieldTextColor(color.Color(theme.FgColor))
+	p.input.SetChangedFunc(p.search)
+	p.input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEsc {
+			p.search("")
+		}
+		p.RemoveItem(p.input)
+		p.input = nil
+		app.SetFocus(p.text)
+	})
+	p.AddItem(p.input, 1, 0, true)
+	app.SetFocus(p.input)
+}
+
+// Highlight case insensitive matches of pattern and jump to the first one, empty pattern clears the search
+func (p *textPage) search(pattern string) {
+	p.pattern = pattern
+	p.matches = 0
+	p.current = 0
+	content := p.content
+	if pattern != "" {
+		content, p.matches = highlightMatches(p.content, searchRegexp(pattern))
+	}
+	p.text.SetRegions(p.matches > 0).SetText(content)
+	p.highlightCurrent()
+}
+
+// Search pattern of page again from its current match, used when the page is rebuilt
+func (p *textPage) searchFrom(previous *textPage) {
+	if previous.pattern == "" {
+		return
+	}
+	p.search(previous.pattern)
+	if p.matches > 0 {
+		p.current = min(previous.current, p.matches-1)
+		p.highlightCurrent()
+	}
+}
+
+// Jump to next match by delta, false without matches
+func (p *textPage) next(delta int) bool {
+	if p.matches == 0 {
+		return false
+	}
+	p.current = (p.current + delta + p.matches) % p.matches
+	p.highlightCurrent()
+	return true
+}
+
+func (p *textPage) highlightCurrent() {
+	if p.matches == 0 {
+		p.text.Highlight()
+	} else {
+		p.text.Highlight(fmt.Sprintf("%s%d", searchRegionPrefix, p.current)).ScrollToHighlight()
+	}
+	p.setTitle()
+}
+
+// Title with search pattern and match counter
+func (p *textPage) setTitle() {
+	if p.pattern == "" {
+		p.text.SetTitle(p.title)
+		return
+	}
+	current := 0
+	if p.matches > 0 {
+		current = p.current + 1
+	}
+	p.text.SetTitle(p.title + fmt.Sprintf("[black:blue]</%s> %d/%d[-:-] ", tview.Escape(p.pattern), current, p.matches))
+}
+
+// Case insensitive regex of pattern, patterns which are not valid regex match literally
+func searchRegexp(pattern string) *regexp.Regexp {
+	if re, err := regexp.Compile("(?i)" + pattern); err == nil {
+		return re
+	}
+	return regexp.MustCompile("(?i)" + regexp.QuoteMeta(pattern))
+}
+
+// Part of tagged text, either a tag kept as is or shown text
+type textSegment struct {
+	tag  string
+	text string
+}
+
+// Split text with color and region tags into tags and shown text
+func splitTags(content string) []textSegment {
+	segments := []textSegment{}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			segments = append(segments, textSegment{text: text.String()})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(content); {
+		if content[i] != '[' {
+			text.WriteByte(content[i])
+			i++
+			continue
+		}
+		if m := textEscapedTagPattern.FindString(content[i:]); m != "" {
+			text.WriteString(m[:len(m)-2] + "]")
+			i += len(m)
+			continue
+		}
+		if m := textTagPattern.FindString(content[i:]); m != "" {
+			flush()
+			segments = append(segments, textSegment{tag: m})
+			i += len(m)
+			continue
+		}
+		text.WriteByte('[')
+		i++
+	}
+	flush()
+	return segments
+}
+
+// Wrap matches of re in shown text of content in numbered regions, returns content and match count
+func highlightMatches(content string, re *regexp.Regexp) (string, int) {
+	segments := splitTags(content)
+	var shown strings.Builder
+	for _, s := range segments {
+		shown.WriteString(s.text)
+	}
+	matches := [][]int{}
+	for _, loc := range re.FindAllStringIndex(shown.String(), -1) {
+		if loc[0] < loc[1] {
+			matches = append(matches, loc)
+		}
+	}
+	if len(matches) == 0 {
+		return content, 0
+	}
+
+	var b strings.Builder
+	offset, m := 0, 0
+	for _, s := range segments {
+		if s.tag != "" {
+			b.WriteString(s.tag)
+			continue
+		}
+		start, end := offset, offset+len(s.text)
+		for pos := start; pos < end; {
+			for m < len(matches) && matches[m][1] <= pos {
+				m++
+			}
+			if m == len(matches) || matches[m][0] >= end {
+				b.WriteString(tview.Escape(s.text[pos-start:]))
+				break
+			}
+			if matches[m][0] > pos {
+				b.WriteString(tview.Escape(s.text[pos-start : matches[m][0]-start]))
+				pos = matches[m][0]
+			}
+			// a match over several segments keeps one region id
+			stop := min(matches[m][1], end)
+			b.WriteString(fmt.Sprintf(`["%s%d"][:%s]%s[:-][""]`, searchRegionPrefix, m, theme.Yellow, tview.Escape(s.text[pos-start:stop-start])))
+			pos = stop
+		}
+		offset = end
+	}
+	return b.String(), len(matches)
+}
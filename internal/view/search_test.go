@@ -0,0 +1,149 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestHighlightMatches(t *testing.T) {
+	match := func(i int, s string) string {
+		return fmt.Sprintf(`["search-%d"][:%s]%s[:-][""]`, i, theme.Yellow, s)
+	}
+	testCases := []struct {
+		name    string
+		content string
+		pattern string
+		want    string
+		count   int
+	}{
+		{
+			name:    "no match keeps content",
+			content: "[blue::b]name[-:-:-]: web",
+			pattern: "api",
+			want:    "[blue::b]name[-:-:-]: web",
+		},
+		{
+			name:    "case insensitive",
+			content: "Error and error",
+			pattern: "error",
+			want:    match(0, "Error") + " and " + match(1, "error"),
+			count:   2,
+		},
+		{
+			name:    "tags are not matched",
+			content: "[blue]blue[-] sky",
+			pattern: "blue",
+			want:    "[blue]" + match(0, "blue") + "[-] sky",
+			count:   1,
+		},
+		{
+			name:    "match over tags keeps region",
+			content: `"[blue::b]status[-:-:-]": "ACTIVE"`,
+			pattern: `status": "act`,
+			want:    `"[blue::b]` + match(0, "status") + `[-:-:-]` + match(0, `": "ACT`) + `IVE"`,
+			count:   1,
+		},
+		{
+			name:    "escaped tags match shown text",
+			content: "see [docs[] here",
+			pattern: `\[docs]`,
+			want:    "see " + match(0, "[docs[]") + " here",
+			count:   1,
+		},
+		{
+			name:    "invalid regex matches literally",
+			content: "a (b c",
+			pattern: "(b",
+			want:    "a " + match(0, "(b") + " c",
+			count:   1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, count := highlightMatches(tc.content, searchRegexp(tc.pattern))
+			if got != tc.want || count != tc.count {
+				t.Errorf("Got %q (%d), Want %q (%d)", got, count, tc.want, tc.count)
+			}
+		})
+	}
+}
+
+func TestTextPageSearch(t *testing.T) {
+	app, _ := newApp(Option{})
+	v := newView(app, []keyDescriptionPair{}, secondaryPageKeyMap{})
+	p := newTextPage("one\ntwo\none more\nthree one", " title ")
+
+	key := func(r rune) *tcell.EventKey {
+		return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+	if v.handleTextSearchInput(p, key('n')) == nil {
+		t.Error("Want: n passed through without a search")
+	}
+
+	p.search("ONE")
+	if p.matches != 3 || !strings.Contains(p.text.GetTitle(), "</ONE> 1/3") {
+		t.Fatalf("Got %d matches, title %q", p.matches, p.text.GetTitle())
+	}
+	if got := p.text.GetHighlights(); len(got) != 1 || got[0] != "search-0" {
+		t.Errorf("Got highlights %v, Want: first match", got)
+	}
+
+	if v.handleTextSearchInput(p, key('N')) != nil || p.current != 2 {
+		t.Errorf("Got match %d, Want: N wraps to last match", p.current)
+	}
+	if v.handleTextSearchInput(p, key('n')) != nil || p.current != 0 {
+		t.Errorf("Got match %d, Want: n wraps to first match", p.current)
+	}
+
+	p.search("four")
+	if !strings.Contains(p.text.GetTitle(), "</four> 0/0") {
+		t.Errorf("Got title %q, Want: no match counter", p.text.GetTitle())
+	}
+
+	p.search("two")
+	if v.handleTextSearchInput(p, tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone)) != nil {
+		t.Error("Want: esc clears the search")
+	}
+	if p.pattern != "" || p.text.GetTitle() != " title " || p.text.GetText(false) != p.content {
+		t.Errorf("Got pattern %q, title %q, Want: search cleared", p.pattern, p.text.GetTitle())
+	}
+	if v.handleTextSearchInput(p, tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone)) == nil {
+		t.Error("Want: esc passed through without a search")
+	}
+}
+
+func TestLogPageSearchKept(t *testing.T) {
+	app := demoApp(t, Option{})
+	app.kind = ServiceKind
+	app.secondaryKind = LogKind
+	service := types.Service{
+		ServiceName:    aws.String("web"),
+		TaskDefinition: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+	}
+	v := newServiceView([]types.Service{service}, app)
+	entity := Entity{service: &service, entityName: "web"}
+	text := showLogsText(&v.view, entity)
+	v.handleSecondaryPageSwitch(entity, text, []byte(text))
+
+	front := func() *textPage {
+		_, item := v.tablePages.GetFrontPage()
+		return item.(*textPage)
+	}
+	front().search("render")
+	if front().matches == 0 {
+		t.Fatal("Want: matches in log page")
+	}
+
+	// selecting a JSON line rebuilds the page
+	if !v.selectLogLine(entity, 1) {
+		t.Fatal("Want: JSON line selected")
+	}
+	if p := front(); p.pattern != "render" || p.matches == 0 {
+		t.Errorf("Got pattern %q with %d matches, Want: search kept", p.pattern, p.matches)
+	}
+}
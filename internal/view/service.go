@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
 	"github.com/keidarcy/e1s/internal/color"
 	"github.com/keidarcy/e1s/internal/utils"
 	"github.com/rivo/tview"
@@ -32,7 +34,10 @@ func newServiceView(services []types.Service, app *App) *serviceView {
 		view: *newView(app, keys, secondaryPageKeyMap{
 			DescriptionKind:   describePageKeys,
 			LogKind:           logPageKeys,
+			LogTailKind:       logTailPageKeys,
+			LogsInsightsKind:  logsInsightsPageKeys,
 			AutoScalingKind:   describePageKeys,
+			MetricsKind:       metricsPageKeys,
 			ServiceEventsKind: otherDescribePageKeys,
 		}),
 		services: services,
@@ -45,37 +50,49 @@ func (app *App) showServicesPage(reload bool) error {
 	}
 
 	var resources []types.Service
-	var err error
-	if len(app.bootstrapServices) > 0 && !reload {
+	bootstrapped := len(app.bootstrapServices) > 0 && !reload
+	if bootstrapped {
 		resources = app.bootstrapServices
 		app.bootstrapServices = nil
-	} else {
-		resources, err = app.Store.ListServices(app.cluster.ClusterName)
-	}
-
-	// Set default service if provided through options
-	if app.Option.Service != "" && !reload {
-		for _, s := range resources {
-			if *s.ServiceName == app.Option.Service {
-				app.service = &s
-				app.events = s.Events
-				err = app.showPrimaryKindPage(TaskKind, false)
-				if err != nil {
-					return err
+	}
+	bootstrapErr := app.bootstrapErr
+	app.bootstrapErr = nil
+	store, clusterName := app.Store, app.cluster.ClusterName
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		if bootstrapped {
+			return bootstrapErr
+		}
+		resources, err = store.ListServices(ctx, clusterName)
+		return err
+	}, func(err error) error {
+		// Set default service if provided through options
+		if app.Option.Service != "" && !reload {
+			for _, s := range resources {
+				if *s.ServiceName == app.Option.Service {
+					app.service = &s
+					app.events = s.Events
+					if err := app.showPrimaryKindPage(TaskKind, false); err != nil {
+						return err
+					}
+					return ErrHandledNavigation
 				}
-				return nil
 			}
+			// If service not found, reset the option and show warning
+			slog.Warn("service not found", "service", app.Option.Service)
+			app.Notice.Warnf("Service '%s' not found in cluster '%s'", app.Option.Service, *app.cluster.ClusterName)
+			app.Option.Service = ""
 		}
-		// If service not found, reset the option and show warning
-		slog.Warn("service not found", "service", app.Option.Service)
-		app.Notice.Warnf("Service '%s' not found in cluster '%s'", app.Option.Service, *app.cluster.ClusterName)
-		app.Option.Service = ""
-	}
 
-	err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
-		return newServiceView(resources, app)
+		var view *serviceView
+		err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
+			view = newServiceView(resources, app)
+			return view
+		})
+		if view != nil && app.Option.ServiceUtilization && (err == nil || isPartial(err)) {
+			view.loadUtilization()
+		}
+		return err
 	})
-	return err
 }
 
 func (v *serviceView) getViewAndFooter() (*view, *tview.TextView) {
@@ -185,6 +202,9 @@ func (v *serviceView) tableParamsBuilder() (title string, headers []string, rows
 		"Task definition",
 		"Age",
 	}
+	if v.app.Option.ServiceUtilization {
+		headers = append(headers, "CPU%", "Mem%")
+	}
 
 	rowsBuilder = func() (data [][]string) {
 		for _, s := range v.services {
@@ -218,6 +238,9 @@ func (v *serviceView) tableParamsBuilder() (title string, headers []string, rows
 			row = append(row, utils.ShowGreenGrey(&enableExecuteCommand, "true"))
 			row = append(row, utils.ArnToName(s.TaskDefinition))
 			row = append(row, utils.Age(s.CreatedAt))
+			if v.app.Option.ServiceUtilization {
+				row = append(row, utilizationCells(v.app.serviceUtilization, *s.ServiceArn)...)
+			}
 			data = append(data, row)
 
 			entity := Entity{service: &s, events: s.Events, entityName: *s.ServiceArn}
@@ -227,3 +250,36 @@ func (v *serviceView) tableParamsBuilder() (title string, headers []string, rows
 	}
 	return
 }
+
+// Fill utilization columns after table renders, in background once the app runs
+func (v *serviceView) loadUtilization() {
+	names := make([]string, 0, len(v.services))
+	for _, s := range v.services {
+		names = append(names, *s.ServiceName)
+	}
+	store, cluster := v.app.Store, v.app.cluster.ClusterName
+	var utilization map[string]api.Utilization
+	v.app.loadPageContent(func(ctx context.Context) (err error) {
+		utilization, err = store.GetServiceUtilization(ctx, cluster, names)
+		return err
+	}, func(err error) {
+		if err != nil {
+			// columns show empty values
+			v.app.Notice.Warnf("failed to get service utilization, err: %v", err)
+		}
+		v.setUtilization(utilization)
+	})
+}
+
+// Keep utilization of services, then set the table cells
+func (v *serviceView) setUtilization(utilization map[string]api.Utilization) {
+	if v.app.serviceUtilization == nil {
+		v.app.serviceUtilization = map[string]api.Utilization{}
+	}
+	for _, s := range v.services {
+		v.app.serviceUtilization[*s.ServiceArn] = utilization[*s.ServiceName]
+	}
+	v.fillUtilization(func(entity Entity) api.Utilization {
+		return v.app.serviceUtilization[*entity.service.ServiceArn]
+	})
+}
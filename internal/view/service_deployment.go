@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
@@ -36,11 +37,16 @@ func (app *App) showServiceDeploymentPage(reload bool) error {
 		return nil
 	}
 
-	resources, err := app.Store.ListServiceDeployments(app.cluster.ClusterName, app.service.ServiceName)
-	err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
-		return newServiceDeploymentView(resources, app)
+	var resources []types.ServiceDeployment
+	store, clusterName, serviceName := app.Store, app.cluster.ClusterName, app.service.ServiceName
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		resources, err = store.ListServiceDeployments(ctx, clusterName, serviceName)
+		return err
+	}, func(err error) error {
+		return buildResourcePage(resources, app, err, func() resourceViewBuilder {
+			return newServiceDeploymentView(resources, app)
+		})
 	})
-	return err
 }
 
 func (v *serviceDeploymentView) getViewAndFooter() (*view, *tview.TextView) {
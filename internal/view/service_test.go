@@ -1,6 +1,8 @@
 package view
 
 import (
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -111,3 +113,31 @@ func TestServiceTableParamsBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceUtilization(t *testing.T) {
+	v := demoServiceView(t, Option{ServiceUtilization: true})
+	app := v.app
+	cpuColumn := slices.Index(v.headers, "CPU%")
+	if cpuColumn < 0 || slices.Index(v.headers, "Mem%") < 0 {
+		t.Fatalf("Got headers %v, Want: CPU%% and Mem%% columns", v.headers)
+	}
+	if text := v.table.GetCell(1, cpuColumn).Text; text != utilizationPending {
+		t.Errorf("Got %s before metrics load, Want: %s", text, utilizationPending)
+	}
+
+	v.loadUtilization()
+	for y := 1; y < v.table.GetRowCount(); y++ {
+		if text := v.table.GetCell(y, cpuColumn).Text; !strings.HasSuffix(text, "%[-:-:-]") {
+			t.Errorf("Got CPU%% %q in row %d, Want: a percentage", text, y)
+		}
+	}
+	if len(app.serviceUtilization) != len(v.services) {
+		t.Errorf("Got %d services utilization kept, Want: %d", len(app.serviceUtilization), len(v.services))
+	}
+
+	v.sortByColumn(cpuColumn)
+	first := v.table.GetCell(1, 0).Text
+	if first != "api" {
+		t.Errorf("Got %s first sorted by CPU%% desc, Want: api", first)
+	}
+}
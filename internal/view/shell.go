@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -35,7 +36,7 @@ func (v *view) execShell() {
 		cmdArgs := append(*args, v.app.Option.Shell)
 		slog.Info("exec", "command", bin+" "+strings.Join(cmdArgs, " "))
 
-		cmd := exec.Command(bin, cmdArgs...)
+		cmd := v.app.cliCommand(bin, cmdArgs)
 		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
 		// ignore the stderr from container
 		_, err = cmd.Stdout.Write([]byte(fmt.Sprintf(execBannerFmt, *v.app.cluster.ClusterName, *v.app.service.ServiceName, utils.ArnToName(v.app.task.TaskArn), containerName)))
@@ -83,7 +84,7 @@ func (v *view) execCommandForm() (*tview.Form, *string) {
 			cmdArgs := append(*args, execCmd)
 			slog.Info("exec", "command", bin+" "+strings.Join(cmdArgs, " "))
 
-			cmd := exec.Command(bin, cmdArgs...)
+			cmd := v.app.cliCommand(bin, cmdArgs)
 			cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
 			_, err = cmd.Stdout.Write([]byte(fmt.Sprintf(execBannerFmt, *v.app.cluster.ClusterName, *v.app.service.ServiceName, utils.ArnToName(v.app.task.TaskArn), containerName)))
 			time.Sleep(1 * time.Second)
@@ -189,7 +190,7 @@ func (v *view) preValidateStartSession() (*[]string, string, error) {
 		if v.app.cluster == nil || v.app.cluster.ClusterName == nil || *v.app.cluster.ClusterName == "" {
 			return nil, "", fmt.Errorf("not a valid cluster")
 		}
-		instanceId, err = v.app.Store.GetTaskInstanceId(v.app.cluster.ClusterName, v.app.task.ContainerInstanceArn)
+		instanceId, err = v.app.Store.GetTaskInstanceId(context.Background(), v.app.cluster.ClusterName, v.app.task.ContainerInstanceArn)
 		if err != nil {
 			return nil, "", fmt.Errorf("failed to get task instance id, err: %v", err)
 		}
@@ -232,7 +233,7 @@ func (v *view) instanceStartSession() {
 		bin, _ := exec.LookPath(awsCli)
 		slog.Info("exec", "command", bin+" "+strings.Join(*args, " "))
 
-		cmd := exec.Command(bin, *args...)
+		cmd := v.app.cliCommand(bin, *args)
 		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
 		_, err = cmd.Stdout.Write([]byte(fmt.Sprintf(instanceBannerFmt, *v.app.cluster.ClusterName, instanceId)))
 		err = cmd.Run()
@@ -302,7 +303,7 @@ func (v *view) instanceStartSessionDocument() {
 		bin, _ := exec.LookPath(awsCli)
 		slog.Info("exec", "command", bin+" "+strings.Join(cmdArgs, " "))
 
-		cmd := exec.Command(bin, cmdArgs...)
+		cmd := v.app.cliCommand(bin, cmdArgs)
 		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
 		_, err = cmd.Stdout.Write([]byte(fmt.Sprintf(execBannerFmt, *v.app.cluster.ClusterName, instanceId, utils.ArnToName(v.app.task.TaskArn), containerName)))
 		err = cmd.Run()
@@ -1,10 +1,12 @@
 package view
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
 )
 
 func TestValidateContainerSessionTarget(t *testing.T) {
@@ -45,3 +47,54 @@ func TestValidateContainerSessionTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestCliArgsEndpoints(t *testing.T) {
+	store, err := api.NewStore("", "us-west-2", api.Endpoints{
+		URL:      "http://localhost:4566",
+		Services: map[string]string{"ssm": "https://vpce.ssm.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, _ := newApp(Option{})
+	app.Store = store
+
+	testCases := []struct {
+		args []string
+		want []string
+	}{
+		{
+			args: []string{"ecs", "execute-command"},
+			want: []string{"--region", "us-west-2", "--endpoint-url", "http://localhost:4566", "ecs", "execute-command"},
+		},
+		{
+			args: []string{"ssm", "start-session"},
+			want: []string{"--region", "us-west-2", "--endpoint-url", "https://vpce.ssm.example", "ssm", "start-session"},
+		},
+	}
+	for _, tc := range testCases {
+		if got := app.cliArgs(tc.args); !slices.Equal(got, tc.want) {
+			t.Errorf("Got: %v, Want: %v", got, tc.want)
+		}
+	}
+
+	// ecs execute-command passes the ssm endpoint to session-manager-plugin through the cli
+	cmd := app.cliCommand("aws", []string{"ecs", "execute-command"})
+	if !slices.Contains(cmd.Env, "AWS_ENDPOINT_URL_SSM=https://vpce.ssm.example") {
+		t.Errorf("Got env without AWS_ENDPOINT_URL_SSM, Want: https://vpce.ssm.example")
+	}
+	if cmd := app.cliCommand("aws", []string{"ssm", "start-session"}); cmd.Env != nil {
+		t.Errorf("Got env %v, Want: inherited env with --endpoint-url", cmd.Env)
+	}
+
+	// stores of other accounts pass their profile
+	profileStore, err := api.NewStore("prod", "eu-west-1", api.Endpoints{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store = profileStore
+	want := []string{"--profile", "prod", "--region", "eu-west-1", "ecs", "execute-command"}
+	if got := app.cliArgs([]string{"ecs", "execute-command"}); !slices.Equal(got, want) {
+		t.Errorf("Got: %v, Want: %v", got, want)
+	}
+}
@@ -1,12 +1,12 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
-	"github.com/keidarcy/e1s/internal/api"
 	"github.com/keidarcy/e1s/internal/color"
 	"github.com/keidarcy/e1s/internal/utils"
 	"github.com/rivo/tview"
@@ -61,30 +61,42 @@ func (app *App) buildSplashPage() *tview.Flex {
 
 func (app *App) runSplashBootstrap() {
 	start := time.Now()
-	store, err := api.NewStore(globalProfile, globalRegion)
+	store, err := newStore(app.Option)
 	var clusters []types.Cluster
+	var clusterProfiles []string
 	var services []types.Service
 	if err == nil {
-		if app.Option.Cluster == "" {
-			clusters, err = store.ListClusters()
+		if app.Option.Cluster == "" && len(globalProfiles) > 0 {
+			clusters, clusterProfiles, err = store.ListClustersInProfiles(context.Background(), globalProfiles, globalRegions)
+		} else if app.Option.Cluster == "" && len(globalRegions) > 0 {
+			clusters, err = store.ListClustersInRegions(context.Background(), globalRegions)
+		} else if app.Option.Cluster == "" {
+			clusters, err = store.ListClusters(context.Background())
 		} else {
 			cn := app.Option.Cluster
-			services, err = store.ListServices(&cn)
+			services, err = store.ListServices(context.Background(), &cn)
 		}
 	}
+	// Partial results are still shown, the page warns about them
+	var bootstrapErr error
+	if isPartial(err) {
+		bootstrapErr, err = err, nil
+	}
 	elapsed := time.Since(start)
 	if err == nil && elapsed < time.Second {
 		time.Sleep(time.Second - elapsed)
 	}
-	app.QueueUpdateDraw(func() {
+	app.dispatch(func() {
 		if err != nil {
 			app.splashStartupErr = err
 			app.Stop()
 			return
 		}
 		app.Store = store
+		app.bootstrapErr = bootstrapErr
 		if app.Option.Cluster == "" {
 			app.bootstrapClusters = clusters
+			app.bootstrapProfiles = clusterProfiles
 		} else {
 			app.bootstrapServices = services
 		}
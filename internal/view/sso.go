@@ -0,0 +1,84 @@
+package view
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/keidarcy/e1s/internal/ui"
+)
+
+const (
+	ssoLoginPage      = "ssoLogin"
+	ssoLoginBannerFmt = "\n\033[1;31m<<E1S-SSO-LOGIN>>\033[0m: \n#######################################\n\033[1;32mProfile\033[0m: \"%s\"\n#######################################\n"
+)
+
+// Offer AWS SSO login when credentials of profile expired.
+// retry runs after a successful login, cancel when the login is declined or fails.
+func (app *App) offerSsoLogin(profile string, retry func(), cancel func()) {
+	if app.Pages.HasPage(ssoLoginPage) {
+		cancel()
+		return
+	}
+	// Keep auto refresh from failing again behind the modal
+	prevSecondaryKind := app.secondaryKind
+	app.secondaryKind = ModalKind
+	closeModal := func() {
+		app.Pages.RemovePage(ssoLoginPage)
+		app.secondaryKind = prevSecondaryKind
+	}
+
+	name := profile
+	if name == "" {
+		name = "default"
+	}
+	title := fmt.Sprintf(" AWS credentials of [purple::b]%s[-:-:-] profile expired, run SSO login?", name)
+	f := ui.StyledForm(title)
+	f.AddButton("Cancel", func() {
+		closeModal()
+		cancel()
+	})
+	f.AddButton("Login", func() {
+		closeModal()
+		if err := app.ssoLogin(profile); err != nil {
+			app.Notice.Warnf("failed to login, err: %v", err)
+			cancel()
+			return
+		}
+		app.Store.InvalidateCredentials()
+		app.refreshContext()
+		app.Notice.Infof("Logged in with %s profile", name)
+		retry()
+	})
+	app.Pages.AddPage(ssoLoginPage, ui.Modal(f, 100, 5, 0, func() {
+		closeModal()
+		cancel()
+	}), true, true)
+}
+
+// Equivalent to
+// aws sso login --profile ${profile}
+// with the terminal handed over to aws cli for the device authorization
+func (app *App) ssoLogin(profile string) error {
+	bin, err := exec.LookPath(awsCli)
+	if err != nil {
+		return err
+	}
+	args := []string{"sso", "login"}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+
+	app.Suspend(func() {
+		app.isSuspended = true
+		slog.Info("exec", "command", bin+" "+strings.Join(args, " "))
+		cmd := exec.Command(bin, args...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		cmd.Stdout.Write([]byte(fmt.Sprintf(ssoLoginBannerFmt, profile)))
+		err = cmd.Run()
+		app.isSuspended = false
+	})
+	return err
+}
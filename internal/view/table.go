@@ -1,8 +1,10 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
@@ -121,7 +123,16 @@ func (v *view) revertProfileOrRegion(to string, prev string) {
 		v.app.kind = RegionKind
 		globalRegion = prev
 	}
-	v.app.Store.SwitchAwsConfig(globalProfile, globalRegion)
+	v.app.markShown()
+	v.switchAwsConfig()
+}
+
+// Switch AWS config to current profile and region, the store in use is the switched one afterwards
+func (v *view) switchAwsConfig() error {
+	err := v.app.Store.SwitchAwsConfig(context.Background(), globalProfile, globalRegion)
+	v.app.Store = v.app.Store.Home().Regional(globalRegion)
+	v.app.refreshContext()
+	return err
 }
 
 // Handle selected event for table when press Enter
@@ -130,20 +141,38 @@ func (v *view) handleSelected(row, column int) {
 		cell := v.table.GetCell(row, column)
 		cell.GetReference()
 		prevProfile := globalProfile
+		prevProfiles := globalProfiles
+		revert := func() {
+			globalProfiles = prevProfiles
+			v.app.markedProfiles = slices.Clone(prevProfiles)
+			v.showMarks(v.app.markedProfiles, profileMarkColumn, profileName)
+			v.revertProfileOrRegion("profiles", prevProfile)
+		}
 		switch entity := cell.GetReference().(type) {
 		case Entity:
 			globalProfile = entity.profile
-			slog.Info("Handle select", "profile", globalProfile)
-			if err := v.app.Store.SwitchAwsConfig(globalProfile, globalRegion); err != nil {
-				v.revertProfileOrRegion("profiles", prevProfile)
+			// Several marked profiles list their clusters together
+			globalProfiles = nil
+			if len(v.app.markedProfiles) > 1 {
+				globalProfiles = slices.Clone(v.app.markedProfiles)
+				if !slices.Contains(globalProfiles, globalProfile) {
+					globalProfile = globalProfiles[0]
+				}
+			}
+			slog.Info("Handle select", "profile", globalProfile, "profiles", globalProfiles)
+			if err := v.switchAwsConfig(); err != nil {
+				revert()
+				v.app.Notice.Warnf("failed to switch AWS config, err: %v", err)
 				return
 			}
+			// Revert when clusters fail to load or loading is cancelled
+			v.app.loadFailed = revert
 			err := v.app.showPrimaryKindPage(ClusterKind, false)
-			if err != nil {
-				v.revertProfileOrRegion("profiles", prevProfile)
+			v.app.loadFailed = nil
+			if err != nil || v.app.load != nil {
 				return
 			}
-			v.app.Notice.Info(fmt.Sprintf("Switched to Profile: %s, Region: %s", globalProfile, globalRegion))
+			v.app.Notice.Info(fmt.Sprintf("Switched to Profile: %s, Region: %s", profilesLabel(), regionsLabel()))
 		}
 		return
 	}
@@ -151,20 +180,38 @@ func (v *view) handleSelected(row, column int) {
 		cell := v.table.GetCell(row, column)
 		cell.GetReference()
 		prevRegion := globalRegion
+		prevRegions := globalRegions
+		revert := func() {
+			globalRegions = prevRegions
+			v.app.markedRegions = slices.Clone(prevRegions)
+			v.showMarks(v.app.markedRegions, regionMarkColumn, regionName)
+			v.revertProfileOrRegion("regions", prevRegion)
+		}
 		switch entity := cell.GetReference().(type) {
 		case Entity:
 			globalRegion = entity.region.Code
-			slog.Info("Handle select", "region", globalRegion)
-			if err := v.app.Store.SwitchAwsConfig(globalProfile, globalRegion); err != nil {
-				v.revertProfileOrRegion("regions", prevRegion)
+			// Several marked regions list their clusters together
+			globalRegions = nil
+			if len(v.app.markedRegions) > 1 {
+				globalRegions = slices.Clone(v.app.markedRegions)
+				if !slices.Contains(globalRegions, globalRegion) {
+					globalRegion = globalRegions[0]
+				}
+			}
+			slog.Info("Handle select", "region", globalRegion, "regions", globalRegions)
+			if err := v.switchAwsConfig(); err != nil {
+				revert()
+				v.app.Notice.Warnf("failed to switch AWS config, err: %v", err)
 				return
 			}
+			// Revert when clusters fail to load or loading is cancelled
+			v.app.loadFailed = revert
 			err := v.app.showPrimaryKindPage(ClusterKind, false)
-			if err != nil {
-				v.revertProfileOrRegion("regions", prevRegion)
+			v.app.loadFailed = nil
+			if err != nil || v.app.load != nil {
 				return
 			}
-			v.app.Notice.Info(fmt.Sprintf("Switched to Profile: %s, Region: %s", globalProfile, globalRegion))
+			v.app.Notice.Info(fmt.Sprintf("Switched to Profile: %s, Region: %s", profilesLabel(), regionsLabel()))
 		}
 
 		return
@@ -173,6 +220,13 @@ func (v *view) handleSelected(row, column int) {
 	if v.app.kind == TaskDefinitionKind || v.app.kind == InstanceKind {
 		return
 	}
+	if v.app.kind == ClusterKind {
+		v.app.useClusterStore(v.app.cluster, v.app.clusterProfile, func() {
+			v.app.rowIndex = 0
+			v.app.showPrimaryKindPage(ServiceKind, false)
+		})
+		return
+	}
 	if v.app.kind == ContainerKind {
 		if v.app.Option.ExecMode == "ssm" {
 			v.instanceStartSessionDocument()
@@ -188,6 +242,15 @@ func (v *view) handleSelected(row, column int) {
 func (v *view) handleInputCapture(event *tcell.EventKey) *tcell.EventKey {
 	// If it's single keystroke, event.Rune() is ascii code
 	switch event.Rune() {
+	case ' ':
+		switch v.app.kind {
+		case RegionKind:
+			v.toggleMark(&v.app.markedRegions, regionMarkColumn, regionName)
+			return nil
+		case ProfileKind:
+			v.toggleMark(&v.app.markedProfiles, profileMarkColumn, profileName)
+			return nil
+		}
 	case 'a':
 		if v.app.kind == ServiceKind {
 			v.app.secondaryKind = AutoScalingKind
@@ -209,8 +272,8 @@ func (v *view) handleInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		}
 	case 'm':
 		if v.app.kind == ServiceKind {
-			v.app.secondaryKind = ModalKind
-			v.showFormModal(v.serviceMetricsForm, 15)
+			v.app.secondaryKind = MetricsKind
+			v.showSecondaryKindPage(false)
 			return event
 		}
 	case 't':
@@ -267,14 +330,18 @@ func (v *view) handleInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		}
 	case 'N':
 		if v.app.kind == ClusterKind {
-			v.app.fromCluster = true
-			v.showKindPage(TaskKind, false)
+			v.app.useClusterStore(v.app.cluster, v.app.clusterProfile, func() {
+				v.app.fromCluster = true
+				v.showKindPage(TaskKind, false)
+			})
 			return event
 		}
 	case 'n':
 		if v.app.kind == ClusterKind {
-			v.app.fromCluster = true
-			v.showKindPage(InstanceKind, false)
+			v.app.useClusterStore(v.app.cluster, v.app.clusterProfile, func() {
+				v.app.fromCluster = true
+				v.showKindPage(InstanceKind, false)
+			})
 			return event
 		}
 	case 'w':
@@ -427,6 +494,7 @@ func (v *view) changeSelectedValues() {
 		cluster := selected.cluster
 		if cluster != nil {
 			v.app.cluster = cluster
+			v.app.clusterProfile = selected.profile
 			v.app.entityName = *cluster.ClusterArn
 		} else {
 			slog.Warn("unexpected in changeSelectedValues", "kind", v.app.kind)
@@ -522,6 +590,7 @@ func (v *view) openInBrowser() {
 		slog.Warn("open failed", "url", url, "kind", v.app.kind, "arn", arn)
 		return
 	}
+	url = utils.ConsoleSignInUrl(v.app.Option.ConsoleURLTemplate, url, arn)
 	slog.Info("open", "url", url)
 	err = utils.OpenURL(url)
 	if err != nil {
@@ -139,6 +139,10 @@ func CompareCellValues(a, b string, columnHeader string, isClusterTasksColumn bo
 		return compareTasksColumn(a, b, sortOrder)
 	}
 
+	if strings.HasSuffix(columnHeader, "%") {
+		return comparePercents(a, b, sortOrder)
+	}
+
 	if utils.IsAge(a) {
 		return compareAges(a, b, sortOrder)
 	}
@@ -206,6 +210,26 @@ func compareNumbers(a, b string, sortOrder string) bool {
 	return aInt > bInt
 }
 
+var colorTagRegexp = regexp.MustCompile(`\[[^\[\]]*\]`)
+
+// comparePercents compares colored percentages (e.g. "[green]12.5%[-:-:-]"),
+// values without a percentage sort below 0%.
+func comparePercents(a, b string, sortOrder string) bool {
+	parse := func(s string) float64 {
+		s = strings.TrimSuffix(colorTagRegexp.ReplaceAllString(s, ""), "%")
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return -1
+		}
+		return f
+	}
+	aFloat, bFloat := parse(a), parse(b)
+	if sortOrder == "asc" {
+		return aFloat < bFloat
+	}
+	return aFloat > bFloat
+}
+
 func compareStrings(a, b string, sortOrder string) bool {
 	if sortOrder == "asc" {
 		return a < b
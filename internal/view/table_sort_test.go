@@ -157,3 +157,19 @@ func TestCompareCellValues_TimeFormat(t *testing.T) {
 	invalid := "2024-01-01 12:00:00"
 	_ = CompareCellValues(valid, invalid, "Created", false, "asc")
 }
+
+func TestCompareCellValues_Percents(t *testing.T) {
+	high := "[#cc241d]92.4%[-:-:-]"
+	low := "[#98971a]8.1%[-:-:-]"
+	empty := "<empty>"
+
+	if !CompareCellValues(high, low, "CPU%", false, "desc") {
+		t.Error("CompareCellValues(92.4%, 8.1%, desc) = false, want true")
+	}
+	if CompareCellValues(high, low, "CPU%", false, "asc") {
+		t.Error("CompareCellValues(92.4%, 8.1%, asc) = true, want false")
+	}
+	if !CompareCellValues(empty, low, "Mem%", false, "asc") {
+		t.Error("CompareCellValues(<empty>, 8.1%, asc) = false, want true")
+	}
+}
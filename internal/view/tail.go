@@ -0,0 +1,206 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/keidarcy/e1s/internal/api"
+	"github.com/keidarcy/e1s/internal/color"
+	"github.com/rivo/tview"
+)
+
+const (
+	// lines kept in live tail page
+	logTailMaxLines = 5000
+	// received log events are rendered together once per interval
+	logTailFlushInterval = 250 * time.Millisecond
+	logTailLineFmt       = "[aqua::]%s[-:-:-] [gray::]%s[-:-:-] %s\n"
+)
+
+// Live tail of log events, keeps streaming while the table is shown
+type logTail struct {
+	// entity name of the tailed resource
+	key    string
+	title  string
+	text   *tview.TextView
+	format *logFormatter
+	cancel context.CancelFunc
+	paused bool
+	// lines received while paused
+	pending []string
+}
+
+// Start live tail of selected resource logs, or show the running tail of it again
+func (v *view) startLogTail(entity Entity) {
+	if t := v.app.tail; t != nil && t.key == entity.entityName {
+		v.showLogTail(entity)
+		return
+	}
+
+	v.loadLogTargets(entity, "tail", func(targets []api.LogTarget) {
+		v.tailLogTargets(entity, targets)
+	})
+}
+
+// Start live tail of targets, the log groups of entity
+func (v *view) tailLogTargets(entity Entity, targets []api.LogTarget) {
+	v.app.stopLogTail()
+	ctx, cancel := context.WithCancel(context.Background())
+	text := tview.NewTextView().SetDynamicColors(true).SetMaxLines(logTailMaxLines)
+	text.SetBorder(true).SetBorderPadding(0, 0, 1, 1)
+	t := &logTail{
+		key:    entity.entityName,
+		title:  fmt.Sprintf(color.TableSecondaryTitleFmt, v.app.kind, entity.entityName, LogTailKind),
+		text:   text,
+		format: v.app.logFormat,
+		cancel: cancel,
+	}
+	t.setTitle()
+	v.app.tail = t
+
+	groups := []string{}
+	for _, target := range targets {
+		groups = append(groups, target.Group)
+	}
+	fmt.Fprintf(text, color.GrayFmt+"\n", "tailing "+strings.Join(groups, ", "))
+
+	events := make(chan api.LogEvent, 256)
+	store := v.app.Store
+	go store.TailLogs(ctx, targets, events)
+	go t.forward(ctx, events, v.app.dispatch)
+
+	v.showLogTail(entity)
+}
+
+// Load awslogs log groups of entity in background, use runs with them once loaded.
+// action names what the log groups are used for in the notice when there is none.
+func (v *view) loadLogTargets(entity Entity, action string, use func(targets []api.LogTarget)) {
+	tdArn, taskId, containerName := v.logScope(entity)
+	if tdArn == nil {
+		return
+	}
+	store := v.app.Store
+	var targets []api.LogTarget
+	v.app.loadSecondaryPage(func(ctx context.Context) (err error) {
+		targets, err = store.GetLogTargets(ctx, tdArn, taskId, containerName)
+		return err
+	}, func(err error) error {
+		if err != nil {
+			v.app.Notice.Warnf("failed to get log groups, err: %v", err)
+			return err
+		}
+		if len(targets) == 0 {
+			v.app.Notice.Warnf("no awslogs log group to %s", action)
+			return nil
+		}
+		use(targets)
+		return nil
+	})
+}
+
+// Show live tail page in table area
+func (v *view) showLogTail(entity Entity) {
+	t := v.app.tail
+	v.app.secondaryKind = LogTailKind
+
+	t.text.SetDoneFunc(func(key tcell.Key) {
+		v.handleTableContentDone(key)
+		v.app.Notice.Info("Live tail keeps running, press ctrl-l in logs page to return")
+	})
+	t.text.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'p':
+			t.togglePause()
+			return nil
+		case 'x':
+			v.app.stopLogTail()
+			v.handleTableContentDone(0)
+			v.app.Notice.Info("Stopped live tail")
+			return nil
+		case 'c':
+			v.app.copyToClipboard("live tail", t.text.GetText(true))
+			return nil
+		}
+		switch event.Key() {
+		case tcell.KeyCtrlZ:
+			v.handleTableContentDone(0)
+			return nil
+		}
+		return event
+	})
+
+	pageName := v.app.kind.getSecondaryPageName(entity.entityName + "." + LogTailKind.String())
+	v.tablePages.AddPage(pageName, t.text, true, true)
+	v.handleHeaderPageSwitch(entity)
+	v.app.Notice.Infof("Viewing %s...", LogTailKind.String())
+}
+
+// Stop running live tail
+func (app *App) stopLogTail() {
+	if app.tail == nil {
+		return
+	}
+	app.tail.cancel()
+	app.tail = nil
+}
+
+// Collect events and render them on the main loop once per logTailFlushInterval
+func (t *logTail) forward(ctx context.Context, events <-chan api.LogEvent, dispatch func(func())) {
+	ticker := time.NewTicker(logTailFlushInterval)
+	defer ticker.Stop()
+	lines := []string{}
+	for {
+		select {
+		case e := <-events:
+			lines = append(lines, fmt.Sprintf(logTailLineFmt, e.Timestamp.Local().Format(time.RFC3339), tview.Escape(e.Stream), t.format.format(e.Message)))
+		case <-ticker.C:
+			if len(lines) == 0 {
+				continue
+			}
+			batch := lines
+			lines = []string{}
+			dispatch(func() {
+				t.append(batch)
+			})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Render lines, or keep them until resumed when paused
+func (t *logTail) append(lines []string) {
+	if t.paused {
+		t.pending = append(t.pending, lines...)
+		if over := len(t.pending) - logTailMaxLines; over > 0 {
+			t.pending = t.pending[over:]
+		}
+		t.setTitle()
+		return
+	}
+	for _, line := range lines {
+		fmt.Fprint(t.text, line)
+	}
+	t.text.ScrollToEnd()
+}
+
+func (t *logTail) togglePause() {
+	t.paused = !t.paused
+	if !t.paused {
+		pending := t.pending
+		t.pending = nil
+		t.append(pending)
+	}
+	t.setTitle()
+}
+
+func (t *logTail) setTitle() {
+	state := "following"
+	if t.paused {
+		state = fmt.Sprintf("paused, %d new", len(t.pending))
+	}
+	t.text.SetTitle(t.title + fmt.Sprintf(color.GrayFmt, "("+state+") "))
+}
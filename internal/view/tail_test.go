@@ -0,0 +1,123 @@
+package view
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
+)
+
+// Log events are dated in the future to be newer than the tail start
+const tailSnapshot = `
+Clusters:
+  - ClusterName: tail-cluster
+    ClusterArn: arn:aws:ecs:us-east-1:111111:cluster/tail-cluster
+    Status: ACTIVE
+TaskDefinitions:
+  - TaskDefinitionArn: arn:aws:ecs:us-east-1:111111:task-definition/app:1
+    ContainerDefinitions:
+      - Name: app
+        LogConfiguration:
+          LogDriver: awslogs
+          Options:
+            awslogs-group: /ecs/app
+            awslogs-stream-prefix: ecs
+      - Name: sidecar
+        LogConfiguration:
+          LogDriver: awslogs
+          Options:
+            awslogs-group: /ecs/sidecar
+Logs:
+  /ecs/app:
+    ecs/app/task1:
+      - Timestamp: 4102444800000
+        Message: app started
+    ecs/app/task2:
+      - Timestamp: 4102444800000
+        Message: other task
+  /ecs/sidecar:
+    sidecar/sidecar/task1:
+      - Timestamp: 4102444801000
+        Message: "[sidecar] ready"
+`
+
+func TestTailLogsMultipleGroups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.yml")
+	if err := os.WriteFile(path, []byte(tailSnapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	app, err := newApp(Option{Fixtures: path, Refresh: -1})
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	targets, err := app.Store.GetLogTargets(ctx, aws.String("arn:aws:ecs:us-east-1:111111:task-definition/app:1"), "task1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[0].Arn != "arn:aws:logs:us-east-1:111111:log-group:/ecs/app" || targets[1].Streams[0] != "sidecar/sidecar/task1" {
+		t.Fatalf("Got targets %+v, Want: /ecs/app and /ecs/sidecar of task1", targets)
+	}
+
+	events := make(chan api.LogEvent)
+	go app.Store.TailLogs(ctx, targets, events)
+	messages := map[string]bool{}
+	for len(messages) < 2 {
+		select {
+		case e := <-events:
+			messages[e.Message] = true
+		case <-ctx.Done():
+			t.Fatalf("Got messages %v before timeout, Want: 2", messages)
+		}
+	}
+	if !messages["app started"] || !messages["[sidecar] ready"] || messages["other task"] {
+		t.Errorf("Got messages %v, Want: task1 messages of both log groups", messages)
+	}
+}
+
+func TestLogTailPause(t *testing.T) {
+	app := demoApp(t, Option{})
+	task := types.Task{
+		TaskArn:           aws.String("arn:aws:ecs:us-east-1:123456789012:task/demo-production/0a1b2c3d4e5f60718293a4b5c6d7e8f9"),
+		TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+	}
+	app.cluster = &types.Cluster{ClusterName: aws.String("demo-production")}
+	app.service = &types.Service{ServiceName: aws.String("web")}
+	app.kind = TaskKind
+	v := newTaskView([]types.Task{task}, false, app)
+	entity := Entity{task: &task, entityName: *task.TaskArn}
+
+	v.startLogTail(entity)
+	defer app.stopLogTail()
+	tail := app.tail
+	if tail == nil || app.secondaryKind != LogTailKind {
+		t.Fatal("Want: live tail started")
+	}
+	v.startLogTail(entity)
+	if app.tail != tail {
+		t.Error("Want: running live tail of the same task shown again")
+	}
+
+	tail.togglePause()
+	tail.append([]string{"line 1\n", "line 2\n"})
+	if strings.Contains(tail.text.GetText(true), "line 1") || len(tail.pending) != 2 {
+		t.Errorf("Got %d pending lines, Want: 2 lines kept while paused", len(tail.pending))
+	}
+	tail.togglePause()
+	if !strings.Contains(tail.text.GetText(true), "line 2") || len(tail.pending) != 0 {
+		t.Error("Want: pending lines rendered on resume")
+	}
+
+	app.stopLogTail()
+	if app.tail != nil {
+		t.Error("Want: live tail stopped")
+	}
+}
@@ -1,11 +1,13 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
 	"github.com/keidarcy/e1s/internal/color"
 	"github.com/keidarcy/e1s/internal/utils"
 	"github.com/rivo/tview"
@@ -16,7 +18,7 @@ type taskView struct {
 	tasks []types.Task
 }
 
-func newTaskView(tasks []types.Task, app *App) *taskView {
+func newTaskView(tasks []types.Task, capped bool, app *App) *taskView {
 	keys := append(basicKeyInputs, []keyDescriptionPair{
 		hotKeyMap["t"],
 		hotKeyMap["L"],
@@ -24,13 +26,19 @@ func newTaskView(tasks []types.Task, app *App) *taskView {
 		hotKeyMap["S"],
 		hotKeyMap["s"],
 	}...)
-	return &taskView{
+	v := &taskView{
 		view: *newView(app, keys, secondaryPageKeyMap{
-			DescriptionKind: describePageKeys,
-			LogKind:         logPageKeys,
+			DescriptionKind:  describePageKeys,
+			LogKind:          taskLogPageKeys,
+			LogTailKind:      logTailPageKeys,
+			LogsInsightsKind: logsInsightsPageKeys,
 		}),
 		tasks: tasks,
 	}
+	if capped {
+		v.titleNote = fmt.Sprintf(color.TableCappedFmt, fmt.Sprintf("first %d shown", api.MaxTasks))
+	}
+	return v
 }
 
 func (app *App) showTasksPages(reload bool) error {
@@ -48,15 +56,64 @@ func (app *App) showTasksPages(reload bool) error {
 		serviceName = nil
 	}
 
-	resources, warnStoppedAfterEmptyRunning, err := app.Store.ListTasks(app.cluster.ClusterName, serviceName, app.taskStatus)
+	var resources []types.Task
+	var info api.TasksInfo
+	store, clusterName, taskStatus := app.Store, app.cluster.ClusterName, app.taskStatus
+	insights := containerInsightsEnhanced(app.cluster) && taskStatus == types.DesiredStatusRunning
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		resources, info, err = store.ListTasks(ctx, clusterName, serviceName, taskStatus)
+		return err
+	}, func(err error) error {
+		// stopped tasks shown instead of running ones have no utilization
+		if !insights || info.StoppedFallback {
+			app.utilization = nil
+		} else if app.utilization == nil {
+			app.utilization = map[string]api.Utilization{}
+		}
+		var view *taskView
+		err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
+			if info.StoppedFallback && len(resources) > 0 {
+				app.Notice.Warn("0 running task show stopped")
+			}
+			view = newTaskView(resources, info.Capped, app)
+			return view
+		})
+		if view != nil && app.utilization != nil && (err == nil || isPartial(err)) {
+			view.loadUtilization()
+		}
+		return err
+	})
+}
+
+// Fill utilization columns after table renders, in background once the app runs
+func (v *taskView) loadUtilization() {
+	store, cluster, tasks := v.app.Store, v.app.cluster.ClusterName, v.tasks
+	var utilization map[string]api.Utilization
+	v.app.loadPageContent(func(ctx context.Context) (err error) {
+		utilization, err = store.GetTaskUtilization(ctx, cluster, tasks)
+		return err
+	}, func(err error) {
+		if err != nil {
+			// columns show empty values
+			v.app.Notice.Warnf("failed to get task utilization, err: %v", err)
+		}
+		v.setUtilization(utilization)
+	})
+}
 
-	err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
-		if warnStoppedAfterEmptyRunning && len(resources) > 0 {
-			app.Notice.Warn("0 running task show stopped")
+// Keep utilization of tasks and their containers, then set the table cells
+func (v *taskView) setUtilization(utilization map[string]api.Utilization) {
+	for _, t := range v.tasks {
+		id := utils.ArnToName(t.TaskArn)
+		v.app.utilization[id] = utilization[id]
+		for _, c := range t.Containers {
+			key := id + "/" + utils.ShowString(c.Name)
+			v.app.utilization[key] = utilization[key]
 		}
-		return newTaskView(resources, app)
+	}
+	v.fillUtilization(func(entity Entity) api.Utilization {
+		return v.app.utilization[utils.ArnToName(entity.task.TaskArn)]
 	})
-	return err
 }
 
 func (v *taskView) getViewAndFooter() (*view, *tview.TextView) {
@@ -148,7 +205,7 @@ func (v *taskView) tableParamsBuilder() (title string, headers []string, rowsBui
 	if v.app.taskStatus == types.DesiredStatusStopped {
 		parent = *v.app.cluster.ClusterName
 	}
-	title = fmt.Sprintf(color.TableTitleFmt, fmt.Sprintf("%s.%s", v.app.kind, strings.ToLower(string(v.app.taskStatus))), parent, len(v.tasks))
+	title = fmt.Sprintf(color.TableTitleFmt, fmt.Sprintf("%s.%s", v.app.kind, strings.ToLower(string(v.app.taskStatus))), parent, len(v.tasks)) + v.titleNote
 	headers = []string{
 		"Task ID",
 		"Last status",
@@ -158,8 +215,11 @@ func (v *taskView) tableParamsBuilder() (title string, headers []string, rowsBui
 		"Containers",
 		"CPU",
 		"Memory",
-		"Age",
 	}
+	if v.app.utilization != nil {
+		headers = append(headers, "CPU%", "Mem%")
+	}
+	headers = append(headers, "Age")
 	rowsBuilder = func() (data [][]string) {
 		for _, t := range v.tasks {
 			// healthy status
@@ -174,6 +234,9 @@ func (v *taskView) tableParamsBuilder() (title string, headers []string, rowsBui
 			row = append(row, strconv.Itoa(len(t.Containers)))
 			row = append(row, utils.ShowString(t.Cpu))
 			row = append(row, utils.ShowString(t.Memory))
+			if v.app.utilization != nil {
+				row = append(row, utilizationCells(v.app.utilization, utils.ArnToName(t.TaskArn))...)
+			}
 			row = append(row, utils.Age(t.StartedAt))
 			data = append(data, row)
 
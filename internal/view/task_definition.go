@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -37,12 +38,16 @@ func (app *App) showTaskDefinitionPage(reload bool) error {
 	if td == nil {
 		td = app.task.TaskDefinitionArn
 	}
-	resources, err := app.Store.ListFullTaskDefinition(td)
-	err = buildResourcePage(resources, app, err, func() resourceViewBuilder {
-		return newTaskDefinitionView(resources, app)
+	var resources []types.TaskDefinition
+	store := app.Store
+	return app.loadPage(reload, func(ctx context.Context) (err error) {
+		resources, err = store.ListFullTaskDefinition(ctx, td)
+		return err
+	}, func(err error) error {
+		return buildResourcePage(resources, app, err, func() resourceViewBuilder {
+			return newTaskDefinitionView(resources, app)
+		})
 	})
-
-	return err
 }
 
 func (v *taskDefinitionView) getViewAndFooter() (*view, *tview.TextView) {
@@ -1,11 +1,15 @@
 package view
 
 import (
+	"context"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/keidarcy/e1s/internal/api"
 	"github.com/keidarcy/e1s/internal/utils"
 )
 
@@ -37,8 +41,8 @@ func getTaskViews() []taskView {
 	app.service = &types.Service{
 		ServiceName: aws.String(serviceName1),
 	}
-	TaskView1 := newTaskView([]types.Task{task1}, app)
-	TaskView2 := newTaskView([]types.Task{task2}, app)
+	TaskView1 := newTaskView([]types.Task{task1}, false, app)
+	TaskView2 := newTaskView([]types.Task{task2}, false, app)
 
 	return []taskView{*TaskView1, *TaskView2}
 }
@@ -113,3 +117,64 @@ func TestTaskTableParamsBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskUtilization(t *testing.T) {
+	v := demoServiceView(t, Option{})
+	app := v.app
+	for i := range v.services {
+		if *v.services[i].ServiceName == "web" {
+			app.service = &v.services[i]
+		}
+	}
+	app.taskStatus = types.DesiredStatusRunning
+	app.kind = TaskKind
+	if err := app.showTasksPages(false); err != nil {
+		t.Fatalf("show tasks page: %v", err)
+	}
+	u := app.utilization["0a1b2c3d4e5f60718293a4b5c6d7e8f9"]
+	if u.CPU == nil || *u.CPU != 35.3 || u.Memory == nil || *u.Memory != 64.4 {
+		t.Fatalf("Got task utilization %+v, Want: 35.3%% CPU and 64.4%% memory", u)
+	}
+	if u := app.utilization["0a1b2c3d4e5f60718293a4b5c6d7e8f9/envoy"]; u.CPU == nil || *u.CPU != 4.1 {
+		t.Errorf("Got envoy utilization %+v, Want: 4.1%% CPU", u)
+	}
+
+	ctx := context.Background()
+	tasks, _, _ := app.Store.ListTasks(ctx, app.cluster.ClusterName, app.service.ServiceName, types.DesiredStatusRunning)
+	_, headers, rowsBuilder := newTaskView(tasks, false, app).tableParamsBuilder()
+	if !slices.Contains(headers, "CPU%") || !slices.Contains(headers, "Mem%") {
+		t.Errorf("Got headers %v, Want: CPU%% and Mem%% columns", headers)
+	}
+	cpuColumn := slices.Index(headers, "CPU%")
+	for _, row := range rowsBuilder() {
+		if row[0] == "0a1b2c3d4e5f60718293a4b5c6d7e8f9" && !strings.Contains(row[cpuColumn], "35.3%") {
+			t.Errorf("Got CPU%% %s, Want: 35.3%%", row[cpuColumn])
+		}
+	}
+
+	// tasks not loaded yet show pending cells
+	app.utilization = map[string]api.Utilization{}
+	for _, row := range rowsBuilder() {
+		if row[cpuColumn] != utilizationPending {
+			t.Errorf("Got CPU%% %s before metrics load, Want: %s", row[cpuColumn], utilizationPending)
+		}
+	}
+
+	clusters, _ := app.Store.ListClusters(ctx)
+	app.cluster = findCluster(clusters, "demo-staging")
+	if err := app.showTasksPages(true); err != nil {
+		t.Fatalf("show tasks page: %v", err)
+	}
+	if app.utilization != nil {
+		t.Errorf("Got utilization %v, Want: nil without Container Insights", app.utilization)
+	}
+
+	// task metrics need enhanced observability
+	app.cluster.Settings = []types.ClusterSetting{{Name: types.ClusterSettingNameContainerInsights, Value: aws.String("enabled")}}
+	if err := app.showTasksPages(true); err != nil {
+		t.Fatalf("show tasks page: %v", err)
+	}
+	if app.utilization != nil {
+		t.Errorf("Got utilization %v, Want: nil with standard Container Insights", app.utilization)
+	}
+}
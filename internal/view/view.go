@@ -1,6 +1,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"time"
@@ -15,7 +16,6 @@ const (
 	smpCi             = "session-manager-plugin"
 	execBannerFmt     = "\n\033[1;31m<<E1S-CONTAINER-SHELL>>\033[0m: \n#######################################\n\033[1;32mCluster\033[0m: \"%s\" \n\033[1;32mService\033[0m: \"%s\" \n\033[1;32mTask\033[0m: \"%s\" \n\033[1;32mContainer\033[0m: \"%s\"\n#######################################\n"
 	instanceBannerFmt = "\n\033[1;31m<<E1S-INSTANCE-SHELL>>\033[0m: \n#######################################\n\033[1;32mCluster\033[0m: \"%s\" \n\033[1;32mInstance\033[0m: \"%s\"\n#######################################\n"
-	realtimeLogFmt    = "\n\033[1;31m<<E1S-LOGS-TAIL>>\033[0m: \n#######################################\n\033[1;32mCluster\033[0m: \"%s\" \n\033[1;32mService\033[0m: \"%s\" \n\033[1;32mLogGroup\033[0m: \"%s\"\n\033[1;32mLogStreamNames\033[0m: \"%s\"\n#######################################\n"
 )
 
 // Base struct of different views
@@ -40,6 +40,8 @@ type view struct {
 	originalRowData [][]string
 	// original reference to handle table events
 	originalRowReferences []Entity
+	// label after table title, e.g. when rows were capped
+	titleNote string
 
 	// Support filter
 	filterActive     bool
@@ -79,12 +81,14 @@ func (v *view) getCurrentSelection() (Entity, error) {
 	}
 }
 
-// Reload current resource
+// Reload current resource, skipping cached responses
 func (v *view) reloadResource(reloadNotice bool) error {
 	if reloadNotice {
 		v.app.Notice.Info("Reloaded")
 	}
+	v.app.bypassCache = true
 	v.showKindPage(v.app.kind, true)
+	v.app.bypassCache = false
 	return nil
 }
 
@@ -109,14 +113,46 @@ func (v *view) showSecondaryKindPage(reload bool) {
 		v.switchToServiceEventsList()
 	case ServiceRevisionKind:
 		v.switchToServiceRevisionJson()
+	case MetricsKind:
+		v.switchToMetrics()
+	case LogsInsightsKind:
+		v.switchToLogsInsights()
 	}
 	if !reload {
-		v.app.Notice.Infof("Viewing %s...", v.app.secondaryKind.String())
+		// Pages loading in background show the notice once loaded
+		if v.app.load == nil {
+			v.app.Notice.Infof("Viewing %s...", v.app.secondaryKind.String())
+		}
 	} else {
 		slog.Debug("Reload", "showSecondaryKindPage", reload)
 	}
 }
 
+// Load content of entity secondary page in background and show it with show. When the
+// page is not on screen yet, a failed or cancelled load goes back to the table.
+func (v *view) loadSecondaryContent(entity Entity, fetch func(ctx context.Context) error, show func(err error) error) {
+	shown := v.secondaryPageShown(entity)
+	if !shown {
+		v.app.loadFailed = func() {
+			v.app.secondaryKind = EmptyKind
+		}
+	}
+	secondaryKind := v.app.secondaryKind
+	v.app.loadSecondaryPage(fetch, func(err error) error {
+		// show may replace the notice, e.g. to warn of a failure
+		if !shown {
+			v.app.Notice.Infof("Viewing %s...", secondaryKind)
+		}
+		return show(err)
+	})
+}
+
+// Whether the current secondary page of entity is on screen
+func (v *view) secondaryPageShown(entity Entity) bool {
+	name, _ := v.tablePages.GetFrontPage()
+	return name == v.app.kind.getSecondaryPageName(entity.entityName+"."+v.app.secondaryKind.String())
+}
+
 // Go current page based on current kind
 func (v *view) closeModal() {
 	v.app.secondaryKind = EmptyKind
@@ -134,24 +170,37 @@ func (v *view) handleSecondaryPageSwitch(entity Entity, colorizedJsonString stri
 	contentTitle := fmt.Sprintf(color.TableSecondaryTitleFmt, v.app.kind, entity.entityName, v.app.secondaryKind)
 	contentPageName := v.app.kind.getSecondaryPageName(entity.entityName + "." + v.app.secondaryKind.String())
 
-	contentTextItem := getSecondaryTextItem(colorizedJsonString, contentTitle)
+	contentPage := newTextPage(colorizedJsonString, contentTitle)
+	contentTextItem := contentPage.text
+
+	// keep search of the page when it is rebuilt
+	if name, item := v.tablePages.GetFrontPage(); name == contentPageName {
+		if previous, ok := item.(*textPage); ok {
+			contentPage.searchFrom(previous)
+		}
+	}
 
 	// press f toggle json
 	contentTextItem.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		fullScreenContent := getSecondaryTextItem(colorizedJsonString, contentTitle)
-
-		// full screen json press ESC close full screen json and back to table
-		fullScreenContent.SetDoneFunc(func(key tcell.Key) {
-			v.handleFullScreenContentDone()
-			v.handleTableContentDone(key)
-		})
-
-		// full screen json press f close full screen json
-		fullScreenContent.SetInputCapture(v.handleFullScreenContentInput(jsonBytes))
+		if v.handleTextSearchInput(contentPage, event) == nil {
+			return nil
+		}
 
 		// contentTextComponent press f open in full screen
 		switch event.Rune() {
 		case 'f':
+			fullScreenContent := newTextPage(colorizedJsonString, contentTitle)
+			fullScreenContent.searchFrom(contentPage)
+
+			// full screen json press ESC close full screen json and back to table
+			fullScreenContent.text.SetDoneFunc(func(key tcell.Key) {
+				v.handleFullScreenContentDone()
+				v.handleTableContentDone(key)
+			})
+
+			// full screen json press f close full screen json
+			fullScreenContent.text.SetInputCapture(v.handleFullScreenContentInput(fullScreenContent, jsonBytes))
+
 			v.app.Pages.AddPage(contentPageName, fullScreenContent, true, true)
 		case 'c':
 			v.app.copyToClipboard("page content", string(jsonBytes))
@@ -159,17 +208,69 @@ func (v *view) handleSecondaryPageSwitch(entity Entity, colorizedJsonString stri
 			v.openInBrowser()
 		case 'r':
 			if v.app.secondaryKind == LogKind {
-				v.realtimeAwsLog(entity)
+				v.startLogTail(entity)
+			}
+		case 'o':
+			if v.app.secondaryKind == LogKind {
+				v.loadOlderLogs(entity)
+				return nil
+			}
+		case 't':
+			if v.app.secondaryKind == LogKind {
+				v.showLogRangeForm(entity, contentTextItem)
+				return nil
+			}
+		case 'i':
+			if v.app.secondaryKind == LogKind {
+				v.showLogsInsightsForm(entity, contentTextItem)
+				return nil
+			}
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			if v.app.secondaryKind == MetricsKind {
+				v.selectMetricsWindow(int(event.Rune() - '1'))
+				return nil
+			}
+			if v.app.secondaryKind == LogKind {
+				v.toggleLogContainer(entity, int(event.Rune()-'1'))
+				return nil
 			}
 		case 'e':
 			if v.app.secondaryKind == DescriptionKind || v.app.secondaryKind == AutoScalingKind || v.app.secondaryKind == ServiceRevisionKind || v.app.secondaryKind == LogKind {
 				v.openInEditor(jsonBytes)
 			}
+		case 'T':
+			if v.app.secondaryKind == DescriptionKind || v.app.secondaryKind == AutoScalingKind || v.app.secondaryKind == ServiceRevisionKind {
+				v.app.jsonTree = true
+				v.showJsonPages(entity)
+				return nil
+			}
+		case 'y':
+			if v.app.secondaryKind == DescriptionKind || v.app.secondaryKind == AutoScalingKind || v.app.secondaryKind == ServiceRevisionKind {
+				v.switchDescribeFormat(entity)
+				return nil
+			}
 		}
 
 		switch event.Key() {
 		case tcell.KeyCtrlR:
 			v.reloadResource(true)
+		case tcell.KeyCtrlL:
+			if v.app.secondaryKind == LogKind {
+				v.startLogTail(entity)
+				return nil
+			}
+		case tcell.KeyTab, tcell.KeyBacktab:
+			delta := 1
+			if event.Key() == tcell.KeyBacktab {
+				delta = -1
+			}
+			if v.app.secondaryKind == LogKind && v.selectLogLine(entity, delta) {
+				return nil
+			}
+		case tcell.KeyEnter:
+			if v.app.secondaryKind == LogKind && v.toggleLogLine(entity) {
+				return nil
+			}
 		case tcell.KeyCtrlZ:
 			v.handleTableContentDone(0)
 		}
@@ -180,7 +281,7 @@ func (v *view) handleSecondaryPageSwitch(entity Entity, colorizedJsonString stri
 
 	slog.Debug("v.tablePages navigation", "action", "AppPage", "pageName", contentPageName, "app", v.app)
 
-	v.tablePages.AddPage(contentPageName, contentTextItem, true, true)
+	v.tablePages.AddPage(contentPageName, contentPage, true, true)
 }
 
 func (v *view) handleHeaderPageSwitch(entity Entity) {